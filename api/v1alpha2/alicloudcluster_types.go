@@ -33,6 +33,10 @@ type AlicloudClusterSpec struct {
 	Network  NetworkSpec `json:"network,omitempty"`
 	ZoneId   string      `json:"zoneId,omitempty"`
 	RegionId string      `json:"regionId,omitempty"`
+
+	// Bastion provisions a jump host in the cluster VPC when set.
+	// +optional
+	Bastion *BastionSpec `json:"bastion,omitempty"`
 }
 
 // AlicloudClusterStatus defines the observed state of AlicloudCluster
@@ -46,6 +50,18 @@ type AlicloudClusterStatus struct {
 	ApiEndpoints []clusterv1.APIEndpoint `json:"apiEndpoints,omitempty"`
 	Reason       string                  `json:"reason,omitempty"`
 	Message      string                  `json:"message,omitempty"`
+
+	// Conditions report whether each network component recorded in Network still matches the cloud.
+	// +optional
+	Conditions Conditions `json:"conditions,omitempty"`
+
+	// Bastion is the observed state of the bastion host.
+	// +optional
+	Bastion Bastion `json:"bastion,omitempty"`
+
+	// LastSyncTime is when the network components were last verified.
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -40,6 +40,65 @@ type AlicloudMachineSpec struct {
 	SystemDiskCategory      string `json:"systemDiskCategory,omitempty"`
 	InstanceType            string `json:"instanceType"`
 	SystemDiskSize          string `json:"systemDiskSize"`
+
+	// AdditionalSecurityGroups are attached to the primary network interface
+	// in addition to the security groups managed for the cluster.
+	// +optional
+	AdditionalSecurityGroups []SecurityGroupReference `json:"additionalSecurityGroups,omitempty"`
+
+	// NetworkInterfaces are secondary elastic network interfaces created together with the instance
+	// and released with it.
+	// +optional
+	NetworkInterfaces []NetworkInterfaceSpec `json:"networkInterfaces,omitempty"`
+}
+
+// SecurityGroupReference selects security groups either by ID or by tags.
+type SecurityGroupReference struct {
+	// ID of the security group.
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// Tags select every security group in the cluster VPC that carries all of the given tags.
+	// Ignored when ID is set.
+	// +optional
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+// NetworkInterfaceSpec describes a secondary elastic network interface.
+type NetworkInterfaceSpec struct {
+	// VSwitchId defaults to the cluster VSwitch.
+	// +optional
+	VSwitchId string `json:"vSwitchId,omitempty"`
+
+	// SecurityGroupId defaults to the security group of the machine's role.
+	// +optional
+	SecurityGroupId string `json:"securityGroupId,omitempty"`
+
+	// PrimaryIpAddress is picked from the VSwitch when empty.
+	// +optional
+	PrimaryIpAddress string `json:"primaryIpAddress,omitempty"`
+
+	// SecondaryPrivateIpAddresses are assigned to the interface once the instance exists.
+	// +optional
+	SecondaryPrivateIpAddresses []string `json:"secondaryPrivateIpAddresses,omitempty"`
+
+	// SecondaryPrivateIpAddressCount is the number of secondary private IPs picked from the VSwitch.
+	// Ignored when SecondaryPrivateIpAddresses is set.
+	// +optional
+	SecondaryPrivateIpAddressCount int `json:"secondaryPrivateIpAddressCount,omitempty"`
+
+	// +optional
+	Description string `json:"description,omitempty"`
+}
+
+// NetworkInterfaceStatus is the observed state of a secondary elastic network interface.
+type NetworkInterfaceStatus struct {
+	ID               string `json:"id,omitempty"`
+	Name             string `json:"name,omitempty"`
+	VSwitchId        string `json:"vSwitchId,omitempty"`
+	PrimaryIpAddress string `json:"primaryIpAddress,omitempty"`
+	// SecondaryIPsAssigned is set once the secondary private IPs in the spec were assigned.
+	SecondaryIPsAssigned bool `json:"secondaryIPsAssigned,omitempty"`
 }
 
 // AlicloudMachineStatus defines the observed state of AlicloudMachine
@@ -61,6 +120,22 @@ type AlicloudMachineStatus struct {
 	Instance *Instance `json:"instance,omitempty"`
 
 	ID string `json:"id,omitempty"`
+
+	// NetworkInterfaces are the secondary elastic network interfaces of the instance.
+	// +optional
+	NetworkInterfaces []NetworkInterfaceStatus `json:"networkInterfaces,omitempty"`
+
+	// PodRoute is the route of the node pod CIDR to this instance, when the cluster manages a route table.
+	// +optional
+	PodRoute *RouteEntry `json:"podRoute,omitempty"`
+
+	// Conditions describe the observed state of the ECS instance, refreshed on every resync.
+	// +optional
+	Conditions Conditions `json:"conditions,omitempty"`
+
+	// LastSyncTime is when the ECS instance was last compared against this AlicloudMachine.
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
 }
 
 type Instance struct {
@@ -0,0 +1,161 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConditionType is the type of a status condition.
+type ConditionType string
+
+const (
+	// InstanceReadyCondition reports whether the ECS instance backing an AlicloudMachine exists and is running.
+	InstanceReadyCondition ConditionType = "InstanceReady"
+	// InstanceSpecSyncedCondition reports whether the live ECS instance still matches the AlicloudMachine spec.
+	InstanceSpecSyncedCondition ConditionType = "InstanceSpecSynced"
+)
+
+// Conditions of an AlicloudCluster, one per network component verified on resync.
+const (
+	VPCReadyCondition           ConditionType = "VPCReady"
+	VSwitchReadyCondition       ConditionType = "VSwitchReady"
+	NatGatewayReadyCondition    ConditionType = "NatGatewayReady"
+	EIPReadyCondition           ConditionType = "EIPReady"
+	SnatEntryReadyCondition     ConditionType = "SnatEntryReady"
+	ForwardEntryReadyCondition  ConditionType = "ForwardEntryReady"
+	EIPPoolReadyCondition       ConditionType = "EIPPoolReady"
+	LoadBalancerReadyCondition  ConditionType = "LoadBalancerReady"
+	ListenerReadyCondition      ConditionType = "ListenerReady"
+	SecurityGroupReadyCondition ConditionType = "SecurityGroupReady"
+
+	ControlPlaneSecurityGroupReadyCondition ConditionType = "ControlPlaneSecurityGroupReady"
+	NodeSecurityGroupReadyCondition         ConditionType = "NodeSecurityGroupReady"
+
+	ExternalLoadBalancerReadyCondition ConditionType = "ExternalLoadBalancerReady"
+	ExternalEIPReadyCondition          ConditionType = "ExternalEIPReady"
+	IPv6LoadBalancerReadyCondition     ConditionType = "IPv6LoadBalancerReady"
+	PrivateZoneReadyCondition          ConditionType = "PrivateZoneReady"
+
+	BastionReadyCondition              ConditionType = "BastionReady"
+	BastionSecurityGroupReadyCondition ConditionType = "BastionSecurityGroupReady"
+
+	BandwidthPackageReadyCondition ConditionType = "BandwidthPackageReady"
+	RouteTableReadyCondition       ConditionType = "RouteTableReady"
+	NetworkAclReadyCondition       ConditionType = "NetworkAclReady"
+	CENAttachedCondition           ConditionType = "CENAttached"
+)
+
+// MachinesDeletedCondition reports whether all AlicloudMachines of a deleting AlicloudCluster are gone,
+// which the network teardown waits for.
+const MachinesDeletedCondition ConditionType = "MachinesDeleted"
+
+const (
+	// WaitingForMachinesReason means the cluster network is kept until its AlicloudMachines are deleted.
+	WaitingForMachinesReason = "WaitingForMachines"
+	// MachinesDeletedReason means no AlicloudMachine of the cluster is left.
+	MachinesDeletedReason = "MachinesDeleted"
+)
+
+const (
+	// ResourceAvailableReason means the resource matches what the provider recorded.
+	ResourceAvailableReason = "Available"
+	// ResourceDriftedReason means the resource was changed or removed outside of the provider.
+	ResourceDriftedReason = "Drifted"
+	// ResourceRepairedReason means drift was detected and repaired by the provider.
+	ResourceRepairedReason = "Repaired"
+)
+
+const (
+	InstanceRunningReason      = "InstanceRunning"
+	InstancePendingReason      = "InstancePending"
+	InstanceStoppedReason      = "InstanceStopped"
+	InstanceNotFoundReason     = "InstanceNotFound"
+	InstanceTypeChangedReason  = "InstanceTypeChanged"
+	InstanceSpecMatchingReason = "InstanceSpecMatching"
+)
+
+// Condition describes one aspect of the observed state of a resource.
+type Condition struct {
+	Type   ConditionType          `json:"type"`
+	Status corev1.ConditionStatus `json:"status"`
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// Conditions is a list of conditions keyed by type.
+type Conditions []Condition
+
+// Get returns the condition of type t, or nil.
+func (c Conditions) Get(t ConditionType) *Condition {
+	for i := range c {
+		if c[i].Type == t {
+			return &c[i]
+		}
+	}
+	return nil
+}
+
+// IsTrue reports whether the condition of type t exists and has status True.
+func (c Conditions) IsTrue(t ConditionType) bool {
+	cond := c.Get(t)
+	return cond != nil && cond.Status == corev1.ConditionTrue
+}
+
+// Set adds or updates the condition of type t. LastTransitionTime only moves when the status changes.
+func (c *Conditions) Set(t ConditionType, status corev1.ConditionStatus, reason, message string) {
+	if cond := c.Get(t); cond != nil {
+		if cond.Status != status {
+			cond.LastTransitionTime = metav1.Now()
+		}
+		cond.Status = status
+		cond.Reason = reason
+		cond.Message = message
+		return
+	}
+	*c = append(*c, Condition{
+		Type:               t,
+		Status:             status,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// Remove drops the condition of type t.
+func (c *Conditions) Remove(t ConditionType) {
+	for i := range *c {
+		if (*c)[i].Type == t {
+			*c = append((*c)[:i], (*c)[i+1:]...)
+			return
+		}
+	}
+}
+
+// MarkTrue sets the condition of type t to True.
+func (c *Conditions) MarkTrue(t ConditionType, reason, message string) {
+	c.Set(t, corev1.ConditionTrue, reason, message)
+}
+
+// MarkFalse sets the condition of type t to False.
+func (c *Conditions) MarkFalse(t ConditionType, reason, message string) {
+	c.Set(t, corev1.ConditionFalse, reason, message)
+}
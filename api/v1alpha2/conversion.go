@@ -1,6 +1,8 @@
 package v1alpha2
 
 import (
+	"strings"
+
 	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/slb"
@@ -16,6 +18,8 @@ func (s *VPCSpec) ConvertToCreateReq() *vpc.CreateVpcRequest {
 	req.Description = s.Description
 	req.VpcName = s.VpcName
 	req.CidrBlock = s.CidrBlock
+	req.EnableIpv6 = requests.NewBoolean(s.EnableIpv6)
+	req.Ipv6CidrBlock = s.Ipv6CidrBlock
 
 	return req
 }
@@ -30,6 +34,7 @@ func (s *VSwitchSpec) ConvertToCreateReq(vpcId string, zoneID string) *vpc.Creat
 	req.VSwitchName = s.VSwitchName
 	req.CidrBlock = s.CidrBlock
 	req.ZoneId = zoneID
+	req.Ipv6CidrBlock = requests.Integer(s.Ipv6CidrBlock)
 
 	return req
 }
@@ -67,6 +72,20 @@ func (s *EIPSpec) ConvertToCreateReq(vpcId string) *vpc.AllocateEipAddressReques
 	return req
 }
 
+func (s *CommonBandwidthPackageSpec) ConvertToCreateReq() *vpc.CreateCommonBandwidthPackageRequest {
+	req := vpc.CreateCreateCommonBandwidthPackageRequest()
+	req.Scheme = "https"
+	req.ClientToken = rand.String(32)
+
+	req.Name = s.Name
+	req.Bandwidth = requests.Integer(s.Bandwidth)
+	req.InternetChargeType = s.InternetChargeType
+	req.ISP = s.ISP
+	req.Ratio = requests.Integer(s.Ratio)
+
+	return req
+}
+
 func (s *SLBSpec) ConvertToCreateReq(vpcId string) *slb.CreateLoadBalancerRequest {
 	req := slb.CreateCreateLoadBalancerRequest()
 	req.Scheme = "https"
@@ -77,6 +96,7 @@ func (s *SLBSpec) ConvertToCreateReq(vpcId string) *slb.CreateLoadBalancerReques
 	req.LoadBalancerName = s.LoadBalancerName
 	req.AddressType = s.AddressType
 	req.Address = s.Address
+	req.VSwitchId = s.VSwitchId
 	req.Bandwidth = requests.Integer(s.Bandwidth)
 	req.AddressIPVersion = s.AddressIPVersion
 	req.LoadBalancerSpec = s.LoadBalancerSpec
@@ -139,33 +159,167 @@ func (s *SecurityGroupSpec) ConvertToCreateReq(vpcId string) *ecs.CreateSecurity
 	return req
 }
 
-func (s *SecurityGroupSpec) ConvertToAuthorizeSecurityGroupRequest(sgID string) []*ecs.AuthorizeSecurityGroupRequest {
-	var list []*ecs.AuthorizeSecurityGroupRequest
-	for _, r := range s.Rules {
-		req := ecs.CreateAuthorizeSecurityGroupRequest()
-		req.Scheme = "https"
-		req.ClientToken = rand.String(32)
-		req.SecurityGroupId = sgID
-
-		req.NicType = r.NicType
-		req.SourcePortRange = r.SourcePortRange
-		req.Description = r.Description
-		req.SourceGroupOwnerId = requests.Integer(r.SourceGroupOwnerId)
-		req.SourceGroupOwnerAccount = r.SourceGroupOwnerAccount
-		req.Ipv6SourceCidrIp = r.Ipv6SourceCidrIp
-		req.Ipv6DestCidrIp = r.Ipv6DestCidrIp
-		req.Policy = r.Policy
-		req.PortRange = r.PortRange
-		req.IpProtocol = r.IpProtocol
-		req.SourceCidrIp = r.SourceCidrIp
-		req.Priority = r.Priority
-		req.DestCidrIp = r.DestCidrIp
-		req.SourceGroupId = r.SourceGroupId
-
-		list = append(list, req)
+const (
+	RuleDirectionIngress = "ingress"
+	RuleDirectionEgress  = "egress"
+)
+
+// IsEgress reports whether the rule applies to outbound traffic.
+func (r *SecurityGroupRuleSpec) IsEgress() bool {
+	return strings.EqualFold(r.Direction, RuleDirectionEgress)
+}
+
+// Key identifies the permission a rule grants, so that a rule from the spec can be matched with
+// the one returned by DescribeSecurityGroupAttribute regardless of defaults and letter case.
+func (r *SecurityGroupRuleSpec) Key() string {
+	direction := RuleDirectionIngress
+	if r.IsEgress() {
+		direction = RuleDirectionEgress
 	}
+	policy := strings.ToLower(r.Policy)
+	if len(policy) == 0 {
+		policy = "accept"
+	}
+	priority := r.Priority
+	if len(priority) == 0 {
+		priority = "1"
+	}
+	sourcePortRange := r.SourcePortRange
+	if sourcePortRange == "-1/-1" {
+		sourcePortRange = ""
+	}
+	sourceCidrIp, destCidrIp := r.SourceCidrIp, r.DestCidrIp
+	if direction == RuleDirectionIngress && len(sourceCidrIp) == 0 && len(r.SourceGroupId) == 0 && len(r.Ipv6SourceCidrIp) == 0 {
+		sourceCidrIp = "0.0.0.0/0"
+	}
+	if direction == RuleDirectionEgress && len(destCidrIp) == 0 && len(r.DestGroupId) == 0 && len(r.Ipv6DestCidrIp) == 0 {
+		destCidrIp = "0.0.0.0/0"
+	}
+
+	return strings.Join([]string{
+		direction,
+		strings.ToLower(r.IpProtocol),
+		r.PortRange,
+		sourcePortRange,
+		sourceCidrIp,
+		r.SourceGroupId,
+		r.Ipv6SourceCidrIp,
+		destCidrIp,
+		r.DestGroupId,
+		r.Ipv6DestCidrIp,
+		policy,
+		priority,
+	}, "|")
+}
+
+func (r *SecurityGroupRuleSpec) ConvertToAuthorizeReq(sgID string) *ecs.AuthorizeSecurityGroupRequest {
+	req := ecs.CreateAuthorizeSecurityGroupRequest()
+	req.Scheme = "https"
+	req.ClientToken = rand.String(32)
+	req.SecurityGroupId = sgID
+
+	req.NicType = r.NicType
+	req.SourcePortRange = r.SourcePortRange
+	req.Description = r.Description
+	req.SourceGroupOwnerId = requests.Integer(r.SourceGroupOwnerId)
+	req.SourceGroupOwnerAccount = r.SourceGroupOwnerAccount
+	req.Ipv6SourceCidrIp = r.Ipv6SourceCidrIp
+	req.Ipv6DestCidrIp = r.Ipv6DestCidrIp
+	req.Policy = r.Policy
+	req.PortRange = r.PortRange
+	req.IpProtocol = r.IpProtocol
+	req.SourceCidrIp = r.SourceCidrIp
+	req.Priority = r.Priority
+	req.DestCidrIp = r.DestCidrIp
+	req.SourceGroupId = r.SourceGroupId
 
-	return list
+	return req
+}
+
+func (r *SecurityGroupRuleSpec) ConvertToAuthorizeEgressReq(sgID string) *ecs.AuthorizeSecurityGroupEgressRequest {
+	req := ecs.CreateAuthorizeSecurityGroupEgressRequest()
+	req.Scheme = "https"
+	req.ClientToken = rand.String(32)
+	req.SecurityGroupId = sgID
+
+	req.NicType = r.NicType
+	req.SourcePortRange = r.SourcePortRange
+	req.Description = r.Description
+	req.Ipv6SourceCidrIp = r.Ipv6SourceCidrIp
+	req.Ipv6DestCidrIp = r.Ipv6DestCidrIp
+	req.Policy = r.Policy
+	req.PortRange = r.PortRange
+	req.IpProtocol = r.IpProtocol
+	req.SourceCidrIp = r.SourceCidrIp
+	req.Priority = r.Priority
+	req.DestCidrIp = r.DestCidrIp
+	req.DestGroupId = r.DestGroupId
+
+	return req
+}
+
+func (r *SecurityGroupRuleSpec) ConvertToRevokeReq(sgID string) *ecs.RevokeSecurityGroupRequest {
+	req := ecs.CreateRevokeSecurityGroupRequest()
+	req.Scheme = "https"
+	req.ClientToken = rand.String(32)
+	req.SecurityGroupId = sgID
+
+	req.NicType = r.NicType
+	req.SourcePortRange = r.SourcePortRange
+	req.SourceGroupOwnerId = requests.Integer(r.SourceGroupOwnerId)
+	req.SourceGroupOwnerAccount = r.SourceGroupOwnerAccount
+	req.Ipv6SourceCidrIp = r.Ipv6SourceCidrIp
+	req.Ipv6DestCidrIp = r.Ipv6DestCidrIp
+	req.Policy = r.Policy
+	req.PortRange = r.PortRange
+	req.IpProtocol = r.IpProtocol
+	req.SourceCidrIp = r.SourceCidrIp
+	req.Priority = r.Priority
+	req.DestCidrIp = r.DestCidrIp
+	req.SourceGroupId = r.SourceGroupId
+
+	return req
+}
+
+func (r *SecurityGroupRuleSpec) ConvertToRevokeEgressReq(sgID string) *ecs.RevokeSecurityGroupEgressRequest {
+	req := ecs.CreateRevokeSecurityGroupEgressRequest()
+	req.Scheme = "https"
+	req.ClientToken = rand.String(32)
+	req.SecurityGroupId = sgID
+
+	req.NicType = r.NicType
+	req.SourcePortRange = r.SourcePortRange
+	req.Ipv6SourceCidrIp = r.Ipv6SourceCidrIp
+	req.Ipv6DestCidrIp = r.Ipv6DestCidrIp
+	req.Policy = r.Policy
+	req.PortRange = r.PortRange
+	req.IpProtocol = r.IpProtocol
+	req.SourceCidrIp = r.SourceCidrIp
+	req.Priority = r.Priority
+	req.DestCidrIp = r.DestCidrIp
+	req.DestGroupId = r.DestGroupId
+
+	return req
+}
+
+func SecurityGroupRuleFromPermission(p *ecs.Permission) SecurityGroupRuleSpec {
+	return SecurityGroupRuleSpec{
+		Direction:               p.Direction,
+		NicType:                 p.NicType,
+		IpProtocol:              p.IpProtocol,
+		SourceCidrIp:            p.SourceCidrIp,
+		PortRange:               p.PortRange,
+		Description:             p.Description,
+		SourceGroupId:           p.SourceGroupId,
+		SourceGroupOwnerAccount: p.SourceGroupOwnerAccount,
+		Priority:                p.Priority,
+		Policy:                  p.Policy,
+		Ipv6SourceCidrIp:        p.Ipv6SourceCidrIp,
+		SourcePortRange:         p.SourcePortRange,
+		DestCidrIp:              p.DestCidrIp,
+		Ipv6DestCidrIp:          p.Ipv6DestCidrIp,
+		DestGroupId:             p.DestGroupId,
+	}
 }
 
 func (s *SLB) FillFrom(desc *slb.LoadBalancer) {
@@ -218,6 +372,7 @@ func (s *VSwitch) FillFrom(desc *vpc.VSwitch) {
 	s.IsDefault = desc.IsDefault
 	s.ResourceGroupId = desc.ResourceGroupId
 	s.NetworkAclId = desc.NetworkAclId
+	s.Ipv6CidrBlock = desc.Ipv6CidrBlock
 }
 
 func (s *NatGateway) FillFrom(resp *vpc.NatGateway) {
@@ -236,6 +391,42 @@ func (s *NatGateway) FillFrom(resp *vpc.NatGateway) {
 	for _, i := range resp.SnatTableIds.SnatTableId {
 		s.SnatTableIds.SnatTableId = append(s.SnatTableIds.SnatTableId, i)
 	}
+	for _, i := range resp.ForwardTableIds.ForwardTableId {
+		s.ForwardTableIds.ForwardTableId = append(s.ForwardTableIds.ForwardTableId, i)
+	}
+}
+
+// Source identifies the entry within its SNAT table, which allows one entry per VSwitch or CIDR.
+func (s *SnatEntry) Source() string {
+	if len(s.SourceCIDR) > 0 {
+		return s.SourceCIDR
+	}
+	return s.SourceVSwitchId
+}
+
+func SnatEntryFromTable(e *vpc.SnatTableEntry) SnatEntry {
+	return SnatEntry{
+		SnatEntryId:     e.SnatEntryId,
+		SourceVSwitchId: e.SourceVSwitchId,
+		SourceCIDR:      e.SourceCIDR,
+		SnatIp:          e.SnatIp,
+	}
+}
+
+// Key identifies the entry within its forward table, which allows one entry per external IP, port and protocol.
+func (s *ForwardEntry) Key() string {
+	return strings.Join([]string{s.ExternalIp, s.ExternalPort, strings.ToLower(s.IpProtocol)}, "|")
+}
+
+func ForwardEntryFromTable(e *vpc.ForwardTableEntry) ForwardEntry {
+	return ForwardEntry{
+		ForwardEntryId: e.ForwardEntryId,
+		ExternalIp:     e.ExternalIp,
+		ExternalPort:   e.ExternalPort,
+		InternalIp:     e.InternalIp,
+		InternalPort:   e.InternalPort,
+		IpProtocol:     e.IpProtocol,
+	}
 }
 
 func (s *EIP) FillFrom(resp *vpc.EipAddress) {
@@ -263,6 +454,131 @@ func (s *EIP) FillFrom(resp *vpc.EipAddress) {
 	s.SecondLimited = resp.SecondLimited
 }
 
+func (s *CommonBandwidthPackage) FillFrom(resp *vpc.CommonBandwidthPackage) {
+	s.BandwidthPackageId = resp.BandwidthPackageId
+	s.Name = resp.Name
+	s.Bandwidth = resp.Bandwidth
+	s.InternetChargeType = resp.InternetChargeType
+	s.ISP = resp.ISP
+	s.Status = resp.Status
+	s.AllocationIds = nil
+	for _, ip := range resp.PublicIpAddresses.PublicIpAddresse {
+		s.AllocationIds = append(s.AllocationIds, ip.AllocationId)
+	}
+}
+
+func (s *RouteTableSpec) ConvertToCreateReq(vpcId string) *vpc.CreateRouteTableRequest {
+	req := vpc.CreateCreateRouteTableRequest()
+	req.Scheme = "https"
+	req.ClientToken = rand.String(32)
+
+	req.VpcId = vpcId
+	req.RouteTableName = s.RouteTableName
+	req.Description = s.Description
+
+	return req
+}
+
+func (s *NetworkAclSpec) ConvertToCreateReq(vpcId string) *vpc.CreateNetworkAclRequest {
+	req := vpc.CreateCreateNetworkAclRequest()
+	req.Scheme = "https"
+	req.ClientToken = rand.String(32)
+
+	req.VpcId = vpcId
+	req.NetworkAclName = s.NetworkAclName
+	req.Description = s.Description
+
+	return req
+}
+
+// Normalize fills the defaults the API applies, so that entries read back compare equal to the spec.
+func (s NetworkAclEntrySpec) Normalize() NetworkAclEntrySpec {
+	s.Policy = strings.ToLower(s.Policy)
+	s.Protocol = strings.ToLower(s.Protocol)
+	if len(s.Port) == 0 {
+		s.Port = "-1/-1"
+	}
+	return s
+}
+
+func (s *NetworkAclEntrySpec) ToIngress() vpc.UpdateNetworkAclEntriesIngressAclEntries {
+	e := s.Normalize()
+	return vpc.UpdateNetworkAclEntriesIngressAclEntries{
+		NetworkAclEntryName: e.Name,
+		Policy:              e.Policy,
+		Protocol:            e.Protocol,
+		SourceCidrIp:        e.CidrIp,
+		Port:                e.Port,
+		Description:         e.Description,
+	}
+}
+
+func (s *NetworkAclEntrySpec) ToEgress() vpc.UpdateNetworkAclEntriesEgressAclEntries {
+	e := s.Normalize()
+	return vpc.UpdateNetworkAclEntriesEgressAclEntries{
+		NetworkAclEntryName: e.Name,
+		Policy:              e.Policy,
+		Protocol:            e.Protocol,
+		DestinationCidrIp:   e.CidrIp,
+		Port:                e.Port,
+		Description:         e.Description,
+	}
+}
+
+func NetworkAclEntryFromIngress(e *vpc.IngressAclEntry) NetworkAclEntrySpec {
+	return NetworkAclEntrySpec{
+		Name:        e.NetworkAclEntryName,
+		Policy:      e.Policy,
+		Protocol:    e.Protocol,
+		CidrIp:      e.SourceCidrIp,
+		Port:        e.Port,
+		Description: e.Description,
+	}.Normalize()
+}
+
+func NetworkAclEntryFromEgress(e *vpc.EgressAclEntry) NetworkAclEntrySpec {
+	return NetworkAclEntrySpec{
+		Name:        e.NetworkAclEntryName,
+		Policy:      e.Policy,
+		Protocol:    e.Protocol,
+		CidrIp:      e.DestinationCidrIp,
+		Port:        e.Port,
+		Description: e.Description,
+	}.Normalize()
+}
+
+// Host is the fully qualified name of the API server record, or "" until the record exists.
+func (s *PrivateZone) Host() string {
+	if len(s.RecordId) == 0 {
+		return ""
+	}
+	return s.Rr + "." + s.ZoneName
+}
+
+func (s *NetworkAcl) FillFrom(desc *vpc.NetworkAclAttribute) {
+	s.NetworkAclId = desc.NetworkAclId
+	s.NetworkAclName = desc.NetworkAclName
+	s.Status = desc.Status
+}
+
+func (s *RouteTable) FillFrom(desc *vpc.RouterTableListType) {
+	s.RouteTableId = desc.RouteTableId
+	s.RouteTableName = desc.RouteTableName
+	s.RouteTableType = desc.RouteTableType
+	s.RouterId = desc.RouterId
+	s.Status = desc.Status
+}
+
+func (s *RouteEntry) FillFrom(desc *vpc.RouteEntry) {
+	s.RouteTableId = desc.RouteTableId
+	s.DestinationCidrBlock = desc.DestinationCidrBlock
+	s.NextHopId = desc.InstanceId
+	if len(desc.NextHops.NextHop) > 0 {
+		s.NextHopId = desc.NextHops.NextHop[0].NextHopId
+	}
+	s.Status = desc.Status
+}
+
 func (s *SecurityGroup) FillFrom(desc *ecs.SecurityGroup) {
 	s.SecurityGroupId = desc.SecurityGroupId
 	s.Description = desc.Description
@@ -275,6 +591,35 @@ func (s *SecurityGroup) FillFrom(desc *ecs.SecurityGroup) {
 	s.ResourceGroupId = desc.ResourceGroupId
 }
 
+func (s *BastionSpec) ConvertToRunInstancesReq(regionID, zoneID, vswitchID, sgID string) *ecs.RunInstancesRequest {
+	req := ecs.CreateRunInstancesRequest()
+	req.Scheme = "https"
+	req.ClientToken = rand.String(32)
+
+	req.RegionId = regionID
+	req.ZoneId = zoneID
+	req.VSwitchId = vswitchID
+	req.SecurityGroupId = sgID
+	req.InstanceName = s.InstanceName
+	req.HostName = s.InstanceName
+	req.InstanceType = s.InstanceType
+	req.ImageId = s.ImageId
+	req.KeyPairName = s.KeyPairName
+	req.Amount = requests.NewInteger(1)
+	req.MinAmount = requests.NewInteger(1)
+
+	return req
+}
+
+func (s *Bastion) FillFrom(instance *ecs.Instance) {
+	s.InstanceId = instance.InstanceId
+	s.InstanceName = instance.InstanceName
+	s.Status = instance.Status
+	if ips := instance.VpcAttributes.PrivateIpAddress.IpAddress; len(ips) > 0 {
+		s.PrivateIpAddress = ips[0]
+	}
+}
+
 func InstanceFromEcs(instance *ecs.Instance) *Instance {
 	if instance == nil {
 		return nil
@@ -20,6 +20,12 @@ const (
 	NGWInitiating = "Initiating"
 	NGWAvailable  = "Available"
 	NGWPending    = "Pending"
+
+	APIServerExternalSLB = "SLB"
+	APIServerExternalEIP = "EIP"
+
+	APIServerEndpointInternal = "internal"
+	APIServerEndpointExternal = "external"
 )
 
 type SLBStatus string
@@ -30,13 +36,99 @@ type NetworkSpec struct {
 	Nat           NatSpec           `json:"nat,omitempty"`
 	SLB           SLBSpec           `json:"slb,omitempty"`
 	SecurityGroup SecurityGroupSpec `json:"securityGroup,omitempty"`
+	// 控制平面节点专用的安全组, 与SecurityGroup一起绑定到控制平面节点
+	// 除Rules外还会自动授权apiserver, etcd, kubelet及Pod网段的Kubernetes规则
+	ControlPlaneSecurityGroup SecurityGroupSpec `json:"controlPlaneSecurityGroup,omitempty"`
+	// 工作节点专用的安全组, 与SecurityGroup一起绑定到工作节点
+	// 除Rules外还会自动授权kubelet, NodePort及Pod网段的Kubernetes规则
+	NodeSecurityGroup SecurityGroupSpec `json:"nodeSecurityGroup,omitempty"`
+
+	// apiserver的访问方式, 默认通过SLB配置的负载均衡访问
+	APIServer APIServerSpec `json:"apiServer,omitempty"`
+
+	// 双栈网络, 开启后VPC和交换机分配IPv6网段, 每个节点分配一个IPv6地址,
+	// 并额外创建一个IPv6公网负载均衡转发到apiserver
+	DualStack bool `json:"dualStack,omitempty"`
+	// DualStack为true时创建的IPv6负载均衡, AddressType固定为internet, AddressIPVersion固定为ipv6
+	IPv6SLB SLBSpec `json:"ipv6SLB,omitempty"`
+
+	// 路由表, 设置后为每个节点的Pod网段(Node.Spec.PodCIDR)添加下一跳为该节点ECS实例的路由,
+	// 用于flannel host-gw, VPC路由等需要VPC转发Pod流量的网络模式。节点删除时路由随之删除
+	RouteTable *RouteTableSpec `json:"routeTable,omitempty"`
+
+	// 网络ACL, 绑定到集群交换机, 在安全组之外提供子网级别的访问控制。
+	// 出入方向规则每次调谐时与spec同步, 规则按顺序匹配
+	NetworkAcl *NetworkAclSpec `json:"networkAcl,omitempty"`
+
+	// 云企业网, 设置后将集群VPC加载到一个已经存在的云企业网实例, 删除集群时卸载
+	CEN *CENSpec `json:"cen,omitempty"`
+
+	// 集群就绪后会周期性地检查 Status.Network 中记录的网络资源,
+	// 开启后自动修复被外部修改的资源(EIP绑定, SNAT条目, SLB监听等), 否则仅通过Conditions报告漂移
+	AutoRepair bool `json:"autoRepair,omitempty"`
+}
+
+// RouteTableSpec 路由表
+// 详细文档见 [CreateRouteTable](https://help.aliyun.com/document_detail/87057.html)
+type RouteTableSpec struct {
+	// 创建一个自定义路由表并绑定到集群交换机, 为false且未指定RouteTableId时使用VPC的系统路由表
+	Custom bool `json:"custom,omitempty"`
+	// 使用一个已经存在的自定义路由表, 删除集群时不会释放
+	RouteTableId string `json:"routeTableId,omitempty"`
+	// 自定义路由表的名称
+	RouteTableName string `json:"routeTableName,omitempty"`
+	// 自定义路由表的描述
+	Description string `json:"description,omitempty"`
+}
+
+// CENSpec 云企业网
+// 详细文档见 [AttachCenChildInstance](https://help.aliyun.com/document_detail/65902.html)
+type CENSpec struct {
+	// 云企业网实例ID
+	CenId string `json:"cenId"`
+	// 发布到云企业网的路由条目的目标网段, 路由条目属于集群使用的路由表(未配置RouteTable时为VPC的系统路由表)。
+	// 从这里删除的网段会被撤回
+	PublishRoutes []string `json:"publishRoutes,omitempty"`
+}
+
+// NetworkAclSpec 网络ACL
+// 详细文档见 [CreateNetworkAcl](https://help.aliyun.com/document_detail/146729.html)
+type NetworkAclSpec struct {
+	// 使用一个已经存在的网络ACL, 删除集群时不会释放; 出入方向规则都为空时不修改它的规则
+	NetworkAclId string `json:"networkAclId,omitempty"`
+	// 网络ACL的名称
+	NetworkAclName string `json:"networkAclName,omitempty"`
+	// 网络ACL的描述
+	Description string `json:"description,omitempty"`
+	// 入方向规则, CidrIp为源地址段
+	IngressEntries []NetworkAclEntrySpec `json:"ingressEntries,omitempty"`
+	// 出方向规则, CidrIp为目的地址段
+	EgressEntries []NetworkAclEntrySpec `json:"egressEntries,omitempty"`
+}
+
+// NetworkAclEntrySpec 网络ACL规则
+type NetworkAclEntrySpec struct {
+	// 规则的名称
+	Name string `json:"name,omitempty"`
+	// 授权策略。取值：
+	//   accept：允许。
+	//   drop：拒绝。
+	Policy string `json:"policy"`
+	// 协议。取值：icmp, gre, tcp, udp, all
+	Protocol string `json:"protocol"`
+	// 源(入方向)或目的(出方向)地址段
+	CidrIp string `json:"cidrIp"`
+	// 端口范围, 如 80/80, 1/65535; 协议为all, icmp或gre时为-1/-1(默认值)
+	Port string `json:"port,omitempty"`
+	// 规则的描述
+	Description string `json:"description,omitempty"`
 }
 
 // VPCSpec 专有网络
 // 使用云资源前, 必须先创建一个专有网络和交换机
 // 详细文档见 [CreateVpc](https://help.aliyun.com/document_detail/35737.html)
 type VPCSpec struct {
-	// 使用一个已经存在的VPC
+	// 使用一个已经存在的VPC, 删除集群时不会释放
 	VpcId string `json:"vpcId,omitempty"`
 
 	// 专有网络名称。长度为2-128个字符，必须以字母或中文开头，可包含数字，点号（.），下划线（_）和短横线（-），但不能以http://或https://开头。
@@ -59,19 +151,17 @@ type VPCSpec struct {
 	//// 需要将网络请求的目的网段设置为ECS或弹性网卡所在VPC的UserCidr。为VPC设置UserCidr后，
 	//// 该VPC中访问UserCidr地址的请求将按照路由表进行转发，而不通过公网IP转发。
 	//UserCidr string `json:"userCidr,omitempty"`
-	//// 是否开启IPv6网段，取值：
-	////   false（默认值）：不开启。
-	////   true：开启。
-	//EnableIpv6 string `json:"enableIpv6,omitempty"`
-	//// VPC的IPv6网段
-	//Ipv6CidrBlock string `json:"ipv6CidrBlock,omitempty"`
+	// 是否开启IPv6网段, DualStack为true时总是开启
+	EnableIpv6 bool `json:"enableIpv6,omitempty"`
+	// VPC的IPv6网段, 为空时由系统分配
+	Ipv6CidrBlock string `json:"ipv6CidrBlock,omitempty"`
 }
 
 // VSwitchSpec 交换机,
 // 使用云资源前, 必须先创建一个专有网络和交换机
 // 详细文档见 [CreateVSwitch](https://help.aliyun.com/document_detail/35745.html)
 type VSwitchSpec struct {
-	// 使用一个已经存在的VSwitch
+	// 使用一个已经存在的VSwitch, 删除集群时不会释放
 	VSwitchId string `json:"vSwitchId,omitempty"`
 
 	// 交换机的名称。
@@ -84,13 +174,17 @@ type VSwitchSpec struct {
 	//   如果交换机的网段与所在VPC的网段相同时，VPC只能有一个交换机。
 	CidrBlock string `json:"cidrBlock,omitempty"`
 
+	// CidrBlock为空时自动分配的网段掩码长度, 取值范围16-29, 默认24。
+	//   从VPC网段中选择第一个与已有交换机、集群Pod网段及Service网段均不重叠的网段。
+	CidrMask int `json:"cidrMask,omitempty"`
+
 	// 交换机的描述信息。
 	//   长度为 2-256个字符，必须以字母或中文开头，但不能以http:// 或https://开头。
 	Description string `json:"description,omitempty"`
 
-	//// 交换机的IPv6网段，支持自定义VPC IPv6网段的最后8bit。取值：0-255（十进制）。
-	////   交换机的IPv6网段掩码默认为64位。
-	//Ipv6CidrBlock string `json:"ipv6CidrBlock,omitempty"`
+	// 交换机的IPv6网段，支持自定义VPC IPv6网段的最后8bit。取值：0-255（十进制）。
+	//   交换机的IPv6网段掩码默认为64位。DualStack为true且未设置时使用0
+	Ipv6CidrBlock string `json:"ipv6CidrBlock,omitempty"`
 }
 
 // NatSpec NAT网关相关配置, 在VPC环境下构建一个公网流量的出入口
@@ -99,12 +193,75 @@ type NatSpec struct {
 	NatGateway NatGatewaySpec `json:"natGateway,omitempty"`
 	//
 	EIP EIPSpec `json:"eip,omitempty"`
+	// SNAT规则, 为空时为集群交换机创建一条使用EIP和EIP池的SNAT规则
+	// 每次调谐时与SNAT表比较: 缺失的规则会被创建, 从这里删除的规则会被删除, 不是由provider创建的规则不会被修改
+	SnatEntries []SnatEntrySpec `json:"snatEntries,omitempty"`
+	// DNAT规则(端口转发), 将EIP的端口映射到VPC内的地址, 调谐方式与SnatEntries相同
+	ForwardEntries []ForwardEntrySpec `json:"forwardEntries,omitempty"`
+	// EIP池, 与上面的EIP一起绑定到NAT网关, 用于增加SNAT可用的端口数
+	// 每一项使用已经存在的EIP(AllocationId)或按配置分配一个新的EIP; 未指定AllocationIds的SNAT规则会同时使用EIP和EIP池中的全部地址
+	EIPs []EIPSpec `json:"eips,omitempty"`
+	// 共享带宽包, 设置后EIP和EIP池中的地址都会加入该带宽包, 共享带宽包的带宽峰值
+	CommonBandwidthPackage *CommonBandwidthPackageSpec `json:"commonBandwidthPackage,omitempty"`
+}
+
+// CommonBandwidthPackageSpec 共享带宽包
+// 详细文档见 [CreateCommonBandwidthPackage](https://help.aliyun.com/document_detail/55781.html)
+type CommonBandwidthPackageSpec struct {
+	// 使用一个已经存在的共享带宽包, 删除集群时不会释放
+	BandwidthPackageId string `json:"bandwidthPackageId,omitempty"`
+	// 共享带宽包的名称
+	Name string `json:"name,omitempty"`
+	// 共享带宽包的带宽峰值, 单位为Mbps, 取值范围 2~20000
+	Bandwidth string `json:"bandwidth,omitempty"`
+	// 共享带宽包的计费方式，取值：
+	//   PayByBandwidth（默认值）：按带宽计费。
+	//   PayBy95：按增强型95计费。
+	//   PayByDominantTraffic：按主流量计费。
+	InternetChargeType string `json:"internetChargeType,omitempty"`
+	// 线路类型，默认值为BGP。
+	ISP string `json:"isp,omitempty"`
+	// 共享带宽包的保底百分比, 仅在InternetChargeType为PayBy95时有效, 取值为20
+	Ratio string `json:"ratio,omitempty"`
+}
+
+// SnatEntrySpec SNAT规则
+// 详细文档见 [CreateSnatEntry](https://help.aliyun.com/document_detail/42672.html)
+type SnatEntrySpec struct {
+	// SNAT条目名称, 默认使用NAT网关名称
+	Name string `json:"name,omitempty"`
+	// 源交换机ID, 该交换机内的ECS实例通过SNAT访问公网。与SourceCIDR都为空时使用集群交换机
+	SourceVSwitchId string `json:"sourceVSwitchId,omitempty"`
+	// 源网段, 设置后忽略SourceVSwitchId
+	SourceCIDR string `json:"sourceCIDR,omitempty"`
+	// SNAT使用的弹性公网IP的实例ID, 未绑定到NAT网关的EIP会被自动绑定。为空时使用NAT网关的EIP和EIP池
+	AllocationIds []string `json:"allocationIds,omitempty"`
+}
+
+// ForwardEntrySpec DNAT规则
+// 详细文档见 [CreateForwardEntry](https://help.aliyun.com/document_detail/36058.html)
+type ForwardEntrySpec struct {
+	// DNAT条目名称
+	Name string `json:"name,omitempty"`
+	// 公网侧使用的弹性公网IP的实例ID, 未绑定到NAT网关的EIP会被自动绑定。为空时使用NAT网关的EIP
+	AllocationId string `json:"allocationId,omitempty"`
+	// 公网侧端口, 取值范围1~65535, 端口范围使用斜线（/）隔开, 例如 1024/1030。取值any时表示全部端口
+	ExternalPort string `json:"externalPort"`
+	// 转发的目的私网IP地址
+	InternalIp string `json:"internalIp"`
+	// 转发的目的端口, 取值范围与ExternalPort相同。默认与ExternalPort相同
+	InternalPort string `json:"internalPort,omitempty"`
+	// 协议类型。取值：
+	//   TCP
+	//   UDP
+	//   Any（默认值）
+	IpProtocol string `json:"ipProtocol,omitempty"`
 }
 
 // NatGatewaySpec NAT网关 在VPC环境下构建一个公网流量的出入口
 // 详细文档见 [CreateNatGateway](https://help.aliyun.com/document_detail/36048.html)
 type NatGatewaySpec struct {
-	// 使用一个已经存在的NAT网关
+	// 使用一个已经存在的NAT网关, 删除集群时不会释放
 	NatGatewayId string `json:"natGatewayId,omitempty"`
 
 	// NAT网关的名称。
@@ -145,7 +302,7 @@ type NatGatewaySpec struct {
 // EIPSpec 弹性公网IP 配置DNAT或SNAT功能前，需要为已创建的NAT网关绑定弹性公网IP
 // 详细文档见 [AllocateEipAddress](https://help.aliyun.com/document_detail/36016.html)
 type EIPSpec struct {
-	// 使用一个已经存在的弹性公网IP
+	// 使用一个已经存在的弹性公网IP, 删除集群时不会释放
 	AllocationId string `json:"allocationId,omitempty"`
 
 	// EIP的带宽峰值，单位为Mbps，默认值为5。
@@ -190,9 +347,9 @@ type EIPSpec struct {
 // 流量分发到apiserver
 // 详细文档见 https://help.aliyun.com/document_detail/27566.html
 type SLBSpec struct {
-	// 使用一个已经存在的负载均衡
+	// 使用一个已经存在的负载均衡, 删除集群时不会释放
 	LoadBalancerId string `json:"loadBalancerId,omitempty"`
-	// 使用一个已经存在的后端服务器组
+	// 使用一个已经存在的后端服务器组, 删除集群时不会释放
 	VServerGroupId string `json:"vServerGroupId,omitempty"`
 
 	// 负载均衡实例的名称。
@@ -210,6 +367,8 @@ type SLBSpec struct {
 	// 后端服务器组名
 	VServerGroupName string `json:"vServerGroupName,omitempty"`
 
+	// 内网负载均衡实例所属的交换机ID, 私有集群模式下默认使用集群交换机
+	VSwitchId string `json:"vSwitchId,omitempty"`
 	// 指定负载均衡实例的私网IP地址，该地址必须包含在交换机的目标网段下。
 	Address string `json:"address,omitempty"`
 	// 负载均衡实例的规格。取值： https://help.aliyun.com/document_detail/85931.html?spm=a2c1g.8271268.0.0.2e90df253aqA3R
@@ -242,15 +401,52 @@ type SLBSpec struct {
 	PricingCycle string `json:"pricingCycle,omitempty"`
 }
 
+// APIServerSpec apiserver的访问方式
+type APIServerSpec struct {
+	// 私有集群模式, 开启后SLB强制使用内网类型(intranet)并绑定到集群交换机, apiserver只能在VPC内访问
+	Private bool `json:"private,omitempty"`
+	// 私有集群的外部访问方式, 仅在Private为true时生效。取值：
+	//   空（默认值）：不提供外部访问。
+	//   SLB：额外创建一个公网负载均衡, 同样转发到控制平面节点。
+	//   EIP：为内网负载均衡绑定一个弹性公网IP。
+	External string `json:"external,omitempty"`
+	// External为SLB时创建的公网负载均衡, AddressType固定为internet
+	ExternalSLB SLBSpec `json:"externalSLB,omitempty"`
+	// External为EIP时绑定到内网负载均衡的弹性公网IP
+	ExternalEIP EIPSpec `json:"externalEIP,omitempty"`
+	// 作为Cluster API端点(Cluster.Status.APIEndpoints)的地址。取值：
+	//   internal：内网负载均衡的地址。
+	//   external：外部访问地址, 配置了External时为默认值。
+	Endpoint string `json:"endpoint,omitempty"`
+	// 云解析PrivateZone, 设置后为apiserver负载均衡的地址维护一条A记录并绑定到集群VPC,
+	// Cluster API端点使用该域名, 负载均衡重建后记录随之更新。该域名需要加入apiserver证书的SAN
+	PrivateZone *PrivateZoneSpec `json:"privateZone,omitempty"`
+}
+
+// PrivateZoneSpec 云解析PrivateZone
+// 详细文档见 [AddZone](https://help.aliyun.com/document_detail/66243.html)
+type PrivateZoneSpec struct {
+	// 使用一个已经存在的Zone, 删除集群时不会释放
+	ZoneId string `json:"zoneId,omitempty"`
+	// 创建的Zone的名称, 如 cluster.internal
+	ZoneName string `json:"zoneName,omitempty"`
+	// apiserver记录的主机记录, 默认为 api
+	RecordName string `json:"recordName,omitempty"`
+	// 记录的TTL, 单位为秒, 默认为60
+	TTL int `json:"ttl,omitempty"`
+}
+
 // SecurityGroupSpec 安全组, 在创建ECS实例时必须指定安全组，每台ECS实例至少属于一个安全组
 // 详细文档见 [CreateSecurityGroup](https://help.aliyun.com/document_detail/25553.html)
 type SecurityGroupSpec struct {
-	// 使用一个已经存在的安全组
+	// 使用一个已经存在的安全组, 删除集群时不会释放
 	SecurityGroupId string `json:"securityGroupId,omitempty"`
 
 	// 安全组名称。长度为2~128个英文或中文字符。必须以大小字母或中文开头，不能以 http://和https://开头。可以包含数字、半角冒号（:）、下划线（_）或者连字符（-）。默认值：空。
 	SecurityGroupName string `json:"securityGroupName,omitempty"`
-	// 安全组入方向规则
+	// 安全组规则, 包括入方向和出方向规则
+	// 每次调谐时都会与安全组中的实际规则比较: 缺失的规则会被授权, 从这里删除的规则会被撤销,
+	// 不是由provider授权的规则不会被修改
 	Rules []*SecurityGroupRuleSpec `json:"rules,omitempty"`
 
 	// 安全组描述信息。长度为2~256个英文或中文字符，不能以 http://和https://开头。 默认值：空。
@@ -261,9 +457,15 @@ type SecurityGroupSpec struct {
 	SecurityGroupType string `json:"securityGroupType,omitempty"`
 }
 
-// SecurityGroupRuleSpec 安全组入方向规则
+// SecurityGroupRuleSpec 安全组规则
 // 详细文档见 [AuthorizeSecurityGroup](https://help.aliyun.com/document_detail/25554.html)
+// 和 [AuthorizeSecurityGroupEgress](https://help.aliyun.com/document_detail/25560.html)
 type SecurityGroupRuleSpec struct {
+	// 规则方向。取值范围：
+	//   ingress：入方向。
+	//   egress：出方向。
+	//   默认值：ingress。
+	Direction string `json:"direction,omitempty"`
 	// 网卡类型。取值范围：
 	//   internet：公网网卡。
 	//   intranet：内网网卡。
@@ -326,6 +528,29 @@ type SecurityGroupRuleSpec struct {
 	//   仅支持VPC类型的IP地址。
 	//   默认值：无。
 	Ipv6DestCidrIp string `json:"ipv6DestCidrIp,omitempty"`
+	// 设置访问权限的目的端安全组ID, 仅用于出方向规则。必须设置DestGroupId或者DestCidrIp参数。
+	DestGroupId string `json:"destGroupId,omitempty"`
+}
+
+// BastionSpec 跳板机, 在集群VPC中创建一台绑定独立安全组和EIP的ECS实例,
+// 用于登录没有公网地址的节点, 集群删除时一并释放
+// 详细文档见 [RunInstances](https://help.aliyun.com/document_detail/63440.html)
+type BastionSpec struct {
+	// 实例名称, 默认值: <集群名>-bastion
+	InstanceName string `json:"instanceName,omitempty"`
+	// 实例规格, 默认值: ecs.t5-lc1m1.small
+	InstanceType string `json:"instanceType,omitempty"`
+	// 镜像ID, 默认与节点使用相同的镜像
+	ImageId string `json:"imageId,omitempty"`
+	// 登录使用的密钥对, 默认使用provider创建的密钥对
+	KeyPairName string `json:"keyPairName,omitempty"`
+	// 允许通过SSH(22端口)访问跳板机的网段, 为空时不允许任何来源访问
+	AllowedCIDRs []string `json:"allowedCIDRs,omitempty"`
+	// 跳板机专用的安全组, 除Rules外还会授权AllowedCIDRs访问22端口,
+	// 控制平面和工作节点的安全组也会允许该安全组访问22端口
+	SecurityGroup SecurityGroupSpec `json:"securityGroup,omitempty"`
+	// 绑定到跳板机的弹性公网IP
+	EIP EIPSpec `json:"eip,omitempty"`
 }
 
 ///////////////////////////////
@@ -336,6 +561,46 @@ type Network struct {
 	SLB           SLB           `json:"slb,omitempty"`
 	Nat           Nat           `json:"nat,omitempty"`
 	SecurityGroup SecurityGroup `json:"securityGroup,omitempty"`
+
+	ControlPlaneSecurityGroup SecurityGroup `json:"controlPlaneSecurityGroup,omitempty"`
+	NodeSecurityGroup         SecurityGroup `json:"nodeSecurityGroup,omitempty"`
+
+	// ExternalSLB is the internet SLB of a private cluster exposed through a second SLB.
+	ExternalSLB SLB `json:"externalSLB,omitempty"`
+	// ExternalEIP is the EIP bound to the intranet SLB of a private cluster.
+	ExternalEIP EIP `json:"externalEIP,omitempty"`
+
+	// IPv6SLB is the IPv6 internet SLB of a dual-stack cluster.
+	IPv6SLB SLB `json:"ipv6SLB,omitempty"`
+
+	// CEN is the attachment of the cluster VPC to a Cloud Enterprise Network instance.
+	CEN CEN `json:"cen,omitempty"`
+
+	// PrivateZone is the PrivateZone zone and record that name the API server.
+	PrivateZone PrivateZone `json:"privateZone,omitempty"`
+
+	// NetworkAcl is the network ACL bound to the cluster VSwitches.
+	NetworkAcl NetworkAcl `json:"networkAcl,omitempty"`
+
+	// RouteTable is the route table that receives the pod CIDR routes of the nodes.
+	RouteTable RouteTable `json:"routeTable,omitempty"`
+
+	// CIDRAllocations are the VSwitch CIDR blocks allocated from the VPC CIDR block. An allocation is
+	// recorded before the VSwitch is created so that a retried creation reuses the same block.
+	CIDRAllocations []CIDRAllocation `json:"cidrAllocations,omitempty"`
+
+	// Owned are the IDs of the resources the provider created, including the NAT entries and the
+	// bastion. Deleting the cluster only releases these; resources adopted by ID are left in place.
+	Owned []string `json:"owned,omitempty"`
+	// OwnershipRecorded is set once Owned is maintained. Owned of a cluster created before it
+	// existed is inferred from the spec once.
+	OwnershipRecorded bool `json:"ownershipRecorded,omitempty"`
+}
+
+// CIDRAllocation is a CIDR block allocated to the VSwitch of a zone.
+type CIDRAllocation struct {
+	ZoneId    string `json:"zoneId,omitempty"`
+	CidrBlock string `json:"cidrBlock,omitempty"`
 }
 
 type VPC struct {
@@ -354,6 +619,56 @@ type VPC struct {
 	CenStatus       string `json:"cenStatus,omitempty"`
 }
 
+type RouteTable struct {
+	RouteTableId   string `json:"routeTableId,omitempty"`
+	RouteTableName string `json:"routeTableName,omitempty"`
+	RouteTableType string `json:"routeTableType,omitempty"`
+	RouterId       string `json:"routerId,omitempty"`
+	Status         string `json:"status,omitempty"`
+	// VSwitchIds are the VSwitches the provider bound to a custom route table.
+	VSwitchIds []string `json:"vSwitchIds,omitempty"`
+}
+
+type CEN struct {
+	CenId  string `json:"cenId,omitempty"`
+	Status string `json:"status,omitempty"`
+	// RouteTableId is the route table whose entries are published.
+	RouteTableId string `json:"routeTableId,omitempty"`
+	// PublishedRoutes are the destination CIDRs the provider published.
+	PublishedRoutes []string `json:"publishedRoutes,omitempty"`
+}
+
+type PrivateZone struct {
+	ZoneId   string `json:"zoneId,omitempty"`
+	ZoneName string `json:"zoneName,omitempty"`
+	// VpcBound is set when the provider bound the cluster VPC to the zone.
+	VpcBound bool   `json:"vpcBound,omitempty"`
+	RecordId string `json:"recordId,omitempty"`
+	Rr       string `json:"rr,omitempty"`
+	Value    string `json:"value,omitempty"`
+}
+
+type NetworkAcl struct {
+	NetworkAclId   string `json:"networkAclId,omitempty"`
+	NetworkAclName string `json:"networkAclName,omitempty"`
+	Status         string `json:"status,omitempty"`
+	// VSwitchIds are the VSwitches the provider associated with the network ACL.
+	VSwitchIds []string `json:"vSwitchIds,omitempty"`
+	// EntriesApplied is set once the provider applied the entries of the spec; IngressEntries and
+	// EgressEntries are the entries it applied, so that a later mismatch with the same spec is drift.
+	EntriesApplied bool                  `json:"entriesApplied,omitempty"`
+	IngressEntries []NetworkAclEntrySpec `json:"ingressEntries,omitempty"`
+	EgressEntries  []NetworkAclEntrySpec `json:"egressEntries,omitempty"`
+}
+
+// RouteEntry is a route whose next hop is an ECS instance.
+type RouteEntry struct {
+	RouteTableId         string `json:"routeTableId,omitempty"`
+	DestinationCidrBlock string `json:"destinationCidrBlock,omitempty"`
+	NextHopId            string `json:"nextHopId,omitempty"`
+	Status               string `json:"status,omitempty"`
+}
+
 type VSwitch struct {
 	VSwitchId               string `json:"vSwitchId,omitempty"`
 	VpcId                   string `json:"vpcId,omitempty"`
@@ -371,31 +686,78 @@ type VSwitch struct {
 }
 
 type Nat struct {
-	NatGateway  NatGateway `json:"natGateway,omitempty"`
-	EIP         EIP        `json:"eip,omitempty"`
-	SnatEntryId string     `json:"snatEntryId,omitempty"`
+	NatGateway NatGateway `json:"natGateway,omitempty"`
+	EIP        EIP        `json:"eip,omitempty"`
+	// SnatEntryId is the entry of the cluster VSwitch created before SnatEntries existed.
+	// It is moved into SnatEntries on the next reconcile.
+	SnatEntryId string `json:"snatEntryId,omitempty"`
+
+	// SnatEntries are the SNAT entries created by the provider; only these are ever deleted.
+	SnatEntries []SnatEntry `json:"snatEntries,omitempty"`
+	// ForwardEntries are the DNAT entries created by the provider; only these are ever deleted.
+	ForwardEntries []ForwardEntry `json:"forwardEntries,omitempty"`
+	// AssociatedEIPs are EIPs referenced by SNAT or DNAT entries that the provider associated
+	// with the NAT gateway. They are unassociated, but not released, on delete.
+	AssociatedEIPs []string `json:"associatedEIPs,omitempty"`
+
+	// EIPs is the EIP pool bound to the NAT gateway besides EIP.
+	EIPs []EIP `json:"eips,omitempty"`
+	// CommonBandwidthPackage is the bandwidth package shared by EIP and EIPs.
+	CommonBandwidthPackage CommonBandwidthPackage `json:"commonBandwidthPackage,omitempty"`
+}
+
+type CommonBandwidthPackage struct {
+	BandwidthPackageId string `json:"bandwidthPackageId,omitempty"`
+	Name               string `json:"name,omitempty"`
+	Bandwidth          string `json:"bandwidth,omitempty"`
+	InternetChargeType string `json:"internetChargeType,omitempty"`
+	ISP                string `json:"isp,omitempty"`
+	Status             string `json:"status,omitempty"`
+	// AllocationIds are the EIPs in the package.
+	AllocationIds []string `json:"allocationIds,omitempty"`
+}
+
+type SnatEntry struct {
+	SnatEntryId     string `json:"snatEntryId,omitempty"`
+	SourceVSwitchId string `json:"sourceVSwitchId,omitempty"`
+	SourceCIDR      string `json:"sourceCIDR,omitempty"`
+	SnatIp          string `json:"snatIp,omitempty"`
+}
+
+type ForwardEntry struct {
+	ForwardEntryId string `json:"forwardEntryId,omitempty"`
+	ExternalIp     string `json:"externalIp,omitempty"`
+	ExternalPort   string `json:"externalPort,omitempty"`
+	InternalIp     string `json:"internalIp,omitempty"`
+	InternalPort   string `json:"internalPort,omitempty"`
+	IpProtocol     string `json:"ipProtocol,omitempty"`
 }
 
 type NatGateway struct {
-	NatGatewayId       string                            `json:"natGatewayId,omitempty"`
-	Name               string                            `json:"name,omitempty"`
-	Description        string                            `json:"description,omitempty"`
-	VpcId              string                            `json:"vpcId,omitempty"`
-	Spec               string                            `json:"spec,omitempty"`
-	InstanceChargeType string                            `json:"instanceChargeType,omitempty"`
-	ExpiredTime        string                            `json:"expiredTime,omitempty"`
-	AutoPay            bool                              `json:"autoPay,omitempty"`
-	BusinessStatus     string                            `json:"businessStatus,omitempty"`
-	CreationTime       string                            `json:"creationTime,omitempty"`
-	Status             string                            `json:"status,omitempty"`
-	DeletionProtection bool                              `json:"deletionProtection,omitempty"`
-	SnatTableIds       SnatTableIdsInDescribeNatGateways `json:"snatTableIds,omitempty"`
+	NatGatewayId       string                               `json:"natGatewayId,omitempty"`
+	Name               string                               `json:"name,omitempty"`
+	Description        string                               `json:"description,omitempty"`
+	VpcId              string                               `json:"vpcId,omitempty"`
+	Spec               string                               `json:"spec,omitempty"`
+	InstanceChargeType string                               `json:"instanceChargeType,omitempty"`
+	ExpiredTime        string                               `json:"expiredTime,omitempty"`
+	AutoPay            bool                                 `json:"autoPay,omitempty"`
+	BusinessStatus     string                               `json:"businessStatus,omitempty"`
+	CreationTime       string                               `json:"creationTime,omitempty"`
+	Status             string                               `json:"status,omitempty"`
+	DeletionProtection bool                                 `json:"deletionProtection,omitempty"`
+	SnatTableIds       SnatTableIdsInDescribeNatGateways    `json:"snatTableIds,omitempty"`
+	ForwardTableIds    ForwardTableIdsInDescribeNatGateways `json:"forwardTableIds,omitempty"`
 }
 
 type SnatTableIdsInDescribeNatGateways struct {
 	SnatTableId []string `json:"SnatTableId" xml:"SnatTableId"`
 }
 
+type ForwardTableIdsInDescribeNatGateways struct {
+	ForwardTableId []string `json:"ForwardTableId" xml:"ForwardTableId"`
+}
+
 type EIP struct {
 	IpAddress          string `json:"ipAddress,omitempty"`
 	PrivateIpAddress   string `json:"privateIpAddress,omitempty"`
@@ -453,4 +815,19 @@ type SecurityGroup struct {
 	AvailableInstanceAmount int    `json:"availableInstanceAmount,omitempty"`
 	EcsCount                int    `json:"ecsCount,omitempty"`
 	ResourceGroupId         string `json:"resourceGroupId,omitempty"`
+
+	// Rules are the rules authorized by the provider; only these are ever revoked.
+	Rules []SecurityGroupRuleSpec `json:"rules,omitempty"`
+}
+
+// Bastion is the observed state of the bastion host.
+type Bastion struct {
+	InstanceId       string `json:"instanceId,omitempty"`
+	InstanceName     string `json:"instanceName,omitempty"`
+	Status           string `json:"status,omitempty"`
+	PrivateIpAddress string `json:"privateIpAddress,omitempty"`
+	PublicIpAddress  string `json:"publicIpAddress,omitempty"`
+
+	SecurityGroup SecurityGroup `json:"securityGroup,omitempty"`
+	EIP           EIP           `json:"eip,omitempty"`
 }
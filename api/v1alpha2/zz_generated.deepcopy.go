@@ -24,6 +24,28 @@ import (
 	apiv1alpha2 "sigs.k8s.io/cluster-api/api/v1alpha2"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIServerSpec) DeepCopyInto(out *APIServerSpec) {
+	*out = *in
+	out.ExternalSLB = in.ExternalSLB
+	out.ExternalEIP = in.ExternalEIP
+	if in.PrivateZone != nil {
+		in, out := &in.PrivateZone, &out.PrivateZone
+		*out = new(PrivateZoneSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServerSpec.
+func (in *APIServerSpec) DeepCopy() *APIServerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(APIServerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AlicloudCluster) DeepCopyInto(out *AlicloudCluster) {
 	*out = *in
@@ -87,6 +109,11 @@ func (in *AlicloudClusterList) DeepCopyObject() runtime.Object {
 func (in *AlicloudClusterSpec) DeepCopyInto(out *AlicloudClusterSpec) {
 	*out = *in
 	in.Network.DeepCopyInto(&out.Network)
+	if in.Bastion != nil {
+		in, out := &in.Bastion, &out.Bastion
+		*out = new(BastionSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlicloudClusterSpec.
@@ -108,6 +135,18 @@ func (in *AlicloudClusterStatus) DeepCopyInto(out *AlicloudClusterStatus) {
 		*out = make([]apiv1alpha2.APIEndpoint, len(*in))
 		copy(*out, *in)
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Bastion.DeepCopyInto(&out.Bastion)
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlicloudClusterStatus.
@@ -125,7 +164,7 @@ func (in *AlicloudMachine) DeepCopyInto(out *AlicloudMachine) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -182,6 +221,20 @@ func (in *AlicloudMachineList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AlicloudMachineSpec) DeepCopyInto(out *AlicloudMachineSpec) {
 	*out = *in
+	if in.AdditionalSecurityGroups != nil {
+		in, out := &in.AdditionalSecurityGroups, &out.AdditionalSecurityGroups
+		*out = make([]SecurityGroupReference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NetworkInterfaces != nil {
+		in, out := &in.NetworkInterfaces, &out.NetworkInterfaces
+		*out = make([]NetworkInterfaceSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlicloudMachineSpec.
@@ -207,6 +260,27 @@ func (in *AlicloudMachineStatus) DeepCopyInto(out *AlicloudMachineStatus) {
 		*out = new(Instance)
 		**out = **in
 	}
+	if in.NetworkInterfaces != nil {
+		in, out := &in.NetworkInterfaces, &out.NetworkInterfaces
+		*out = make([]NetworkInterfaceStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodRoute != nil {
+		in, out := &in.PodRoute, &out.PodRoute
+		*out = new(RouteEntry)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlicloudMachineStatus.
@@ -224,7 +298,7 @@ func (in *AlicloudMachineTemplate) DeepCopyInto(out *AlicloudMachineTemplate) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlicloudMachineTemplate.
@@ -280,7 +354,7 @@ func (in *AlicloudMachineTemplateList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AlicloudMachineTemplateResource) DeepCopyInto(out *AlicloudMachineTemplateResource) {
 	*out = *in
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlicloudMachineTemplateResource.
@@ -296,7 +370,7 @@ func (in *AlicloudMachineTemplateResource) DeepCopy() *AlicloudMachineTemplateRe
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AlicloudMachineTemplateSpec) DeepCopyInto(out *AlicloudMachineTemplateSpec) {
 	*out = *in
-	out.Template = in.Template
+	in.Template.DeepCopyInto(&out.Template)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlicloudMachineTemplateSpec.
@@ -309,6 +383,172 @@ func (in *AlicloudMachineTemplateSpec) DeepCopy() *AlicloudMachineTemplateSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Bastion) DeepCopyInto(out *Bastion) {
+	*out = *in
+	in.SecurityGroup.DeepCopyInto(&out.SecurityGroup)
+	out.EIP = in.EIP
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Bastion.
+func (in *Bastion) DeepCopy() *Bastion {
+	if in == nil {
+		return nil
+	}
+	out := new(Bastion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BastionSpec) DeepCopyInto(out *BastionSpec) {
+	*out = *in
+	if in.AllowedCIDRs != nil {
+		in, out := &in.AllowedCIDRs, &out.AllowedCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.SecurityGroup.DeepCopyInto(&out.SecurityGroup)
+	out.EIP = in.EIP
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BastionSpec.
+func (in *BastionSpec) DeepCopy() *BastionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BastionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CEN) DeepCopyInto(out *CEN) {
+	*out = *in
+	if in.PublishedRoutes != nil {
+		in, out := &in.PublishedRoutes, &out.PublishedRoutes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CEN.
+func (in *CEN) DeepCopy() *CEN {
+	if in == nil {
+		return nil
+	}
+	out := new(CEN)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CENSpec) DeepCopyInto(out *CENSpec) {
+	*out = *in
+	if in.PublishRoutes != nil {
+		in, out := &in.PublishRoutes, &out.PublishRoutes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CENSpec.
+func (in *CENSpec) DeepCopy() *CENSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CENSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CIDRAllocation) DeepCopyInto(out *CIDRAllocation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CIDRAllocation.
+func (in *CIDRAllocation) DeepCopy() *CIDRAllocation {
+	if in == nil {
+		return nil
+	}
+	out := new(CIDRAllocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommonBandwidthPackage) DeepCopyInto(out *CommonBandwidthPackage) {
+	*out = *in
+	if in.AllocationIds != nil {
+		in, out := &in.AllocationIds, &out.AllocationIds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommonBandwidthPackage.
+func (in *CommonBandwidthPackage) DeepCopy() *CommonBandwidthPackage {
+	if in == nil {
+		return nil
+	}
+	out := new(CommonBandwidthPackage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommonBandwidthPackageSpec) DeepCopyInto(out *CommonBandwidthPackageSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommonBandwidthPackageSpec.
+func (in *CommonBandwidthPackageSpec) DeepCopy() *CommonBandwidthPackageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CommonBandwidthPackageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Condition) DeepCopyInto(out *Condition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Condition.
+func (in *Condition) DeepCopy() *Condition {
+	if in == nil {
+		return nil
+	}
+	out := new(Condition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in Conditions) DeepCopyInto(out *Conditions) {
+	{
+		in := &in
+		*out = make(Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Conditions.
+func (in Conditions) DeepCopy() Conditions {
+	if in == nil {
+		return nil
+	}
+	out := new(Conditions)
+	in.DeepCopyInto(out)
+	return *out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EIP) DeepCopyInto(out *EIP) {
 	*out = *in
@@ -339,6 +579,56 @@ func (in *EIPSpec) DeepCopy() *EIPSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ForwardEntry) DeepCopyInto(out *ForwardEntry) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ForwardEntry.
+func (in *ForwardEntry) DeepCopy() *ForwardEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(ForwardEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ForwardEntrySpec) DeepCopyInto(out *ForwardEntrySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ForwardEntrySpec.
+func (in *ForwardEntrySpec) DeepCopy() *ForwardEntrySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ForwardEntrySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ForwardTableIdsInDescribeNatGateways) DeepCopyInto(out *ForwardTableIdsInDescribeNatGateways) {
+	*out = *in
+	if in.ForwardTableId != nil {
+		in, out := &in.ForwardTableId, &out.ForwardTableId
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ForwardTableIdsInDescribeNatGateways.
+func (in *ForwardTableIdsInDescribeNatGateways) DeepCopy() *ForwardTableIdsInDescribeNatGateways {
+	if in == nil {
+		return nil
+	}
+	out := new(ForwardTableIdsInDescribeNatGateways)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Instance) DeepCopyInto(out *Instance) {
 	*out = *in
@@ -359,6 +649,27 @@ func (in *Nat) DeepCopyInto(out *Nat) {
 	*out = *in
 	in.NatGateway.DeepCopyInto(&out.NatGateway)
 	out.EIP = in.EIP
+	if in.SnatEntries != nil {
+		in, out := &in.SnatEntries, &out.SnatEntries
+		*out = make([]SnatEntry, len(*in))
+		copy(*out, *in)
+	}
+	if in.ForwardEntries != nil {
+		in, out := &in.ForwardEntries, &out.ForwardEntries
+		*out = make([]ForwardEntry, len(*in))
+		copy(*out, *in)
+	}
+	if in.AssociatedEIPs != nil {
+		in, out := &in.AssociatedEIPs, &out.AssociatedEIPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.EIPs != nil {
+		in, out := &in.EIPs, &out.EIPs
+		*out = make([]EIP, len(*in))
+		copy(*out, *in)
+	}
+	in.CommonBandwidthPackage.DeepCopyInto(&out.CommonBandwidthPackage)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Nat.
@@ -375,6 +686,7 @@ func (in *Nat) DeepCopy() *Nat {
 func (in *NatGateway) DeepCopyInto(out *NatGateway) {
 	*out = *in
 	in.SnatTableIds.DeepCopyInto(&out.SnatTableIds)
+	in.ForwardTableIds.DeepCopyInto(&out.ForwardTableIds)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NatGateway.
@@ -407,6 +719,28 @@ func (in *NatSpec) DeepCopyInto(out *NatSpec) {
 	*out = *in
 	out.NatGateway = in.NatGateway
 	out.EIP = in.EIP
+	if in.SnatEntries != nil {
+		in, out := &in.SnatEntries, &out.SnatEntries
+		*out = make([]SnatEntrySpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ForwardEntries != nil {
+		in, out := &in.ForwardEntries, &out.ForwardEntries
+		*out = make([]ForwardEntrySpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.EIPs != nil {
+		in, out := &in.EIPs, &out.EIPs
+		*out = make([]EIPSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.CommonBandwidthPackage != nil {
+		in, out := &in.CommonBandwidthPackage, &out.CommonBandwidthPackage
+		*out = new(CommonBandwidthPackageSpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NatSpec.
@@ -426,7 +760,26 @@ func (in *Network) DeepCopyInto(out *Network) {
 	out.VSwitch = in.VSwitch
 	out.SLB = in.SLB
 	in.Nat.DeepCopyInto(&out.Nat)
-	out.SecurityGroup = in.SecurityGroup
+	in.SecurityGroup.DeepCopyInto(&out.SecurityGroup)
+	in.ControlPlaneSecurityGroup.DeepCopyInto(&out.ControlPlaneSecurityGroup)
+	in.NodeSecurityGroup.DeepCopyInto(&out.NodeSecurityGroup)
+	out.ExternalSLB = in.ExternalSLB
+	out.ExternalEIP = in.ExternalEIP
+	out.IPv6SLB = in.IPv6SLB
+	in.CEN.DeepCopyInto(&out.CEN)
+	out.PrivateZone = in.PrivateZone
+	in.NetworkAcl.DeepCopyInto(&out.NetworkAcl)
+	in.RouteTable.DeepCopyInto(&out.RouteTable)
+	if in.CIDRAllocations != nil {
+		in, out := &in.CIDRAllocations, &out.CIDRAllocations
+		*out = make([]CIDRAllocation, len(*in))
+		copy(*out, *in)
+	}
+	if in.Owned != nil {
+		in, out := &in.Owned, &out.Owned
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Network.
@@ -439,14 +792,138 @@ func (in *Network) DeepCopy() *Network {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkAcl) DeepCopyInto(out *NetworkAcl) {
+	*out = *in
+	if in.VSwitchIds != nil {
+		in, out := &in.VSwitchIds, &out.VSwitchIds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IngressEntries != nil {
+		in, out := &in.IngressEntries, &out.IngressEntries
+		*out = make([]NetworkAclEntrySpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.EgressEntries != nil {
+		in, out := &in.EgressEntries, &out.EgressEntries
+		*out = make([]NetworkAclEntrySpec, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkAcl.
+func (in *NetworkAcl) DeepCopy() *NetworkAcl {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkAcl)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkAclEntrySpec) DeepCopyInto(out *NetworkAclEntrySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkAclEntrySpec.
+func (in *NetworkAclEntrySpec) DeepCopy() *NetworkAclEntrySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkAclEntrySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkAclSpec) DeepCopyInto(out *NetworkAclSpec) {
+	*out = *in
+	if in.IngressEntries != nil {
+		in, out := &in.IngressEntries, &out.IngressEntries
+		*out = make([]NetworkAclEntrySpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.EgressEntries != nil {
+		in, out := &in.EgressEntries, &out.EgressEntries
+		*out = make([]NetworkAclEntrySpec, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkAclSpec.
+func (in *NetworkAclSpec) DeepCopy() *NetworkAclSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkAclSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkInterfaceSpec) DeepCopyInto(out *NetworkInterfaceSpec) {
+	*out = *in
+	if in.SecondaryPrivateIpAddresses != nil {
+		in, out := &in.SecondaryPrivateIpAddresses, &out.SecondaryPrivateIpAddresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkInterfaceSpec.
+func (in *NetworkInterfaceSpec) DeepCopy() *NetworkInterfaceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkInterfaceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkInterfaceStatus) DeepCopyInto(out *NetworkInterfaceStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkInterfaceStatus.
+func (in *NetworkInterfaceStatus) DeepCopy() *NetworkInterfaceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkInterfaceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkSpec) DeepCopyInto(out *NetworkSpec) {
 	*out = *in
 	out.VPC = in.VPC
 	out.VSwitch = in.VSwitch
-	out.Nat = in.Nat
+	in.Nat.DeepCopyInto(&out.Nat)
 	out.SLB = in.SLB
 	in.SecurityGroup.DeepCopyInto(&out.SecurityGroup)
+	in.ControlPlaneSecurityGroup.DeepCopyInto(&out.ControlPlaneSecurityGroup)
+	in.NodeSecurityGroup.DeepCopyInto(&out.NodeSecurityGroup)
+	in.APIServer.DeepCopyInto(&out.APIServer)
+	out.IPv6SLB = in.IPv6SLB
+	if in.RouteTable != nil {
+		in, out := &in.RouteTable, &out.RouteTable
+		*out = new(RouteTableSpec)
+		**out = **in
+	}
+	if in.NetworkAcl != nil {
+		in, out := &in.NetworkAcl, &out.NetworkAcl
+		*out = new(NetworkAclSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CEN != nil {
+		in, out := &in.CEN, &out.CEN
+		*out = new(CENSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkSpec.
@@ -459,6 +936,86 @@ func (in *NetworkSpec) DeepCopy() *NetworkSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrivateZone) DeepCopyInto(out *PrivateZone) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrivateZone.
+func (in *PrivateZone) DeepCopy() *PrivateZone {
+	if in == nil {
+		return nil
+	}
+	out := new(PrivateZone)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrivateZoneSpec) DeepCopyInto(out *PrivateZoneSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrivateZoneSpec.
+func (in *PrivateZoneSpec) DeepCopy() *PrivateZoneSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PrivateZoneSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RouteEntry) DeepCopyInto(out *RouteEntry) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteEntry.
+func (in *RouteEntry) DeepCopy() *RouteEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(RouteEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RouteTable) DeepCopyInto(out *RouteTable) {
+	*out = *in
+	if in.VSwitchIds != nil {
+		in, out := &in.VSwitchIds, &out.VSwitchIds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteTable.
+func (in *RouteTable) DeepCopy() *RouteTable {
+	if in == nil {
+		return nil
+	}
+	out := new(RouteTable)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RouteTableSpec) DeepCopyInto(out *RouteTableSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteTableSpec.
+func (in *RouteTableSpec) DeepCopy() *RouteTableSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RouteTableSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SLB) DeepCopyInto(out *SLB) {
 	*out = *in
@@ -492,6 +1049,11 @@ func (in *SLBSpec) DeepCopy() *SLBSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecurityGroup) DeepCopyInto(out *SecurityGroup) {
 	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]SecurityGroupRuleSpec, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityGroup.
@@ -504,6 +1066,28 @@ func (in *SecurityGroup) DeepCopy() *SecurityGroup {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityGroupReference) DeepCopyInto(out *SecurityGroupReference) {
+	*out = *in
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityGroupReference.
+func (in *SecurityGroupReference) DeepCopy() *SecurityGroupReference {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityGroupReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecurityGroupRuleSpec) DeepCopyInto(out *SecurityGroupRuleSpec) {
 	*out = *in
@@ -545,6 +1129,41 @@ func (in *SecurityGroupSpec) DeepCopy() *SecurityGroupSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnatEntry) DeepCopyInto(out *SnatEntry) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnatEntry.
+func (in *SnatEntry) DeepCopy() *SnatEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(SnatEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnatEntrySpec) DeepCopyInto(out *SnatEntrySpec) {
+	*out = *in
+	if in.AllocationIds != nil {
+		in, out := &in.AllocationIds, &out.AllocationIds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnatEntrySpec.
+func (in *SnatEntrySpec) DeepCopy() *SnatEntrySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SnatEntrySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SnatTableIdsInDescribeNatGateways) DeepCopyInto(out *SnatTableIdsInDescribeNatGateways) {
 	*out = *in
@@ -0,0 +1,429 @@
+package controllers
+
+import (
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-alicloud/api/v1alpha2"
+	"sigs.k8s.io/cluster-api-provider-alicloud/pkg/aliyun"
+	"sigs.k8s.io/cluster-api-provider-alicloud/pkg/aliyun/retry"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha2"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const apiServerPort = 6443
+
+// slbSpec returns Spec.Network.SLB adjusted for the API server access mode.
+// An intranet SLB has to be placed in a VSwitch, which defaults to the cluster one.
+func (s *ClusterProcessor) slbSpec() infrav1.SLBSpec {
+	spec := s.alicloudCluster.Spec.Network.SLB
+	if s.alicloudCluster.Spec.Network.APIServer.Private {
+		spec.AddressType = "intranet"
+	}
+	if spec.AddressType == "intranet" && len(spec.VSwitchId) == 0 {
+		spec.VSwitchId = s.alicloudCluster.Status.Network.VSwitch.VSwitchId
+	}
+	return spec
+}
+
+// externalAccess returns how a private cluster is reached from outside the VPC, or "" if it is not.
+func (s *ClusterProcessor) externalAccess() string {
+	apiServer := s.alicloudCluster.Spec.Network.APIServer
+	if !apiServer.Private {
+		return ""
+	}
+	return apiServer.External
+}
+
+// apiEndpoint selects the address published as the Cluster API endpoint: the PrivateZone record when
+// there is one, the external address of a private cluster unless the internal one was asked for, and the
+// address of the SLB otherwise.
+func (s *ClusterProcessor) apiEndpoint() clusterv1.APIEndpoint {
+	network := &s.alicloudCluster.Status.Network
+
+	if host := network.PrivateZone.Host(); len(host) > 0 {
+		return clusterv1.APIEndpoint{Host: host, Port: apiServerPort}
+	}
+
+	host := network.SLB.Address
+	if s.alicloudCluster.Spec.Network.APIServer.Endpoint != infrav1.APIServerEndpointInternal {
+		switch s.externalAccess() {
+		case infrav1.APIServerExternalSLB:
+			host = network.ExternalSLB.Address
+		case infrav1.APIServerExternalEIP:
+			host = network.ExternalEIP.IpAddress
+		}
+	}
+	return clusterv1.APIEndpoint{Host: host, Port: apiServerPort}
+}
+
+// reconcileAPIServerAccess exposes a private cluster outside the VPC when asked to, adds the IPv6 SLB of a
+// dual-stack cluster and the PrivateZone record, and publishes the API endpoints.
+func (s *ClusterProcessor) reconcileAPIServerAccess() (reconcile.Result, error) {
+	switch mode := s.externalAccess(); mode {
+	case "":
+	case infrav1.APIServerExternalSLB:
+		if err := s.reconcileExternalSLB(); err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "reconcileExternalSLB")
+		}
+	case infrav1.APIServerExternalEIP:
+		if err := s.reconcileExternalEIP(); err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "reconcileExternalEIP")
+		}
+	default:
+		return reconcile.Result{}, errors.Errorf("unknown external access mode %q, must be %q or %q",
+			mode, infrav1.APIServerExternalSLB, infrav1.APIServerExternalEIP)
+	}
+
+	if s.dualStack() {
+		if err := s.reconcileAccessSLB(s.ipv6SLBSpec(), &s.alicloudCluster.Status.Network.IPv6SLB, "IPv6"); err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "reconcileIPv6SLB")
+		}
+	}
+
+	if err := s.reconcilePrivateZone(); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "reconcilePrivateZone")
+	}
+
+	if err := s.publishAPIEndpoints(); err != nil {
+		return reconcile.Result{}, err
+	}
+	_ = s.patch()
+	return reconcile.Result{}, nil
+}
+
+// apiServerAccessPending reports whether the spec asks for an external SLB or EIP, or an IPv6 SLB,
+// that has not been set up yet, e.g. because it was added after the cluster became ready.
+func (s *ClusterProcessor) apiServerAccessPending() bool {
+	network := &s.alicloudCluster.Status.Network
+	switch s.externalAccess() {
+	case infrav1.APIServerExternalSLB:
+		if len(network.ExternalSLB.VServerGroupId) == 0 {
+			return true
+		}
+	case infrav1.APIServerExternalEIP:
+		if len(network.ExternalEIP.AllocationId) == 0 {
+			return true
+		}
+	}
+	return s.dualStack() && len(network.IPv6SLB.VServerGroupId) == 0
+}
+
+// publishAPIEndpoints records the API endpoints in the status.
+func (s *ClusterProcessor) publishAPIEndpoints() error {
+	endpoint := s.apiEndpoint()
+	if len(endpoint.Host) == 0 {
+		return errors.New("API endpoint address not allocated yet")
+	}
+	s.alicloudCluster.Status.ApiEndpoints = []clusterv1.APIEndpoint{endpoint}
+	// the first endpoint is the one Cluster API uses, the IPv6 one is only published
+	if host := s.alicloudCluster.Status.Network.IPv6SLB.Address; len(host) > 0 {
+		s.alicloudCluster.Status.ApiEndpoints = append(s.alicloudCluster.Status.ApiEndpoints,
+			clusterv1.APIEndpoint{Host: host, Port: apiServerPort})
+	}
+	return nil
+}
+
+func (s *ClusterProcessor) reconcileExternalSLB() error {
+	return s.reconcileAccessSLB(s.externalSLBSpec(), &s.alicloudCluster.Status.Network.ExternalSLB, "external")
+}
+
+// externalSLBSpec returns Spec.Network.APIServer.ExternalSLB forced to an internet SLB.
+func (s *ClusterProcessor) externalSLBSpec() infrav1.SLBSpec {
+	spec := s.alicloudCluster.Spec.Network.APIServer.ExternalSLB
+	spec.AddressType = "internet"
+	return spec
+}
+
+// reconcileAccessSLB creates an additional SLB that forwards to the control-plane nodes next to the
+// apiserver SLB, with its own VServer group and TCP listener. Every step is recorded as it completes, so a
+// failed pass resumes where it stopped. kind names it in events.
+func (s *ClusterProcessor) reconcileAccessSLB(spec infrav1.SLBSpec, status *infrav1.SLB, kind string) error {
+	s.Info("reconcileAccessSLB", "kind", kind)
+
+	id := status.LoadBalancerId
+	if len(id) == 0 {
+		var err error
+		id, err = s.slb.Create(spec, s.alicloudCluster.Status.Network.VPC.VpcId)
+		if err != nil {
+			s.warningf("FailedCreateSLB", err, "Failed to create %s SLB", kind)
+			return errors.Wrap(err, "Create")
+		}
+		s.eventf("SuccessfulCreateSLB", "Created %s SLB %s", kind, id)
+		s.own(id)
+		status.LoadBalancerId = id
+		_ = s.patch()
+	}
+
+	if len(status.VServerGroupId) == 0 {
+		target, err := s.slb.WaitReady(id)
+		if err != nil {
+			return errors.Wrapf(err, "WaitRead %v", id)
+		}
+		target.DeepCopyInto(status)
+
+		vsgID, err := s.slb.CreateServerGroup(spec, id)
+		if err != nil {
+			s.warningf("FailedCreateVServerGroup", err, "Failed to create VServer group on SLB %s", id)
+			return errors.Wrapf(err, "CreateServerGroup %v", id)
+		}
+		s.eventf("SuccessfulCreateVServerGroup", "Created VServer group %s on SLB %s", vsgID, id)
+		s.own(vsgID)
+		status.VServerGroupId = vsgID
+		_ = s.patch()
+	}
+
+	if _, err := s.reconcileAccessListener(spec, status); err != nil {
+		return err
+	}
+
+	s.Info("reconcileAccessSLB success", "kind", kind, "status", status)
+	return nil
+}
+
+// reconcileAccessListener creates the TCP listener of an additional SLB if it is missing and starts it.
+// It reports whether anything had to be done.
+func (s *ClusterProcessor) reconcileAccessListener(spec infrav1.SLBSpec, status *infrav1.SLB) (bool, error) {
+	id := status.LoadBalancerId
+	listener, err := s.slb.DescribeListenerStatus(id)
+	if err != nil {
+		return false, errors.Wrapf(err, "DescribeListenerStatus %v", id)
+	}
+	if listener == "starting" || listener == "running" {
+		return false, nil
+	}
+
+	if len(listener) == 0 {
+		if err := s.slb.CreateTCPListener(spec, id, status.VServerGroupId); err != nil {
+			s.warningf("FailedCreateListener", err, "Failed to create TCP listener on SLB %s", id)
+			return false, errors.Wrapf(err, "CreateTCPListener %v", id)
+		}
+		s.eventf("SuccessfulCreateListener", "Created TCP listener on SLB %s", id)
+	}
+	if err := s.slb.StartListener(id); err != nil {
+		s.warningf("FailedStartListener", err, "Failed to start TCP listener on SLB %s", id)
+		return false, errors.Wrapf(err, "StartListener %v", id)
+	}
+	return true, nil
+}
+
+func (s *ClusterProcessor) reconcileExternalEIP() error {
+	network := &s.alicloudCluster.Status.Network
+	eip := &network.ExternalEIP
+	slbID := network.SLB.LoadBalancerId
+
+	if len(eip.AllocationId) == 0 {
+		s.Info("reconcileExternalEIP")
+
+		id, err := s.vpc.CreateEIP(s.alicloudCluster.Spec.Network.APIServer.ExternalEIP, network.VPC.VpcId)
+		if err != nil {
+			s.warningf("FailedCreateEIP", err, "Failed to allocate EIP for SLB %s", slbID)
+			return errors.Wrap(err, "CreateEIP")
+		}
+		s.eventf("SuccessfulCreateEIP", "Allocated EIP %s for SLB %s", id, slbID)
+		s.own(id)
+		target, err := s.vpc.WaitEIPStatus(id, infrav1.EIPAvailable)
+		if err != nil {
+			return errors.Wrapf(err, "WaitEIPStatus %v", id)
+		}
+		target.DeepCopyInto(eip)
+		_ = s.patch()
+	}
+	if eip.Status == infrav1.EIPInUse && eip.InstanceId == slbID {
+		return nil
+	}
+
+	if err := s.vpc.AssociateEipToInstance(eip.AllocationId, slbID, aliyun.EipInstanceTypeSlb); err != nil {
+		s.warningf("FailedAssociateEIP", err, "Failed to associate EIP %s with SLB %s", eip.AllocationId, slbID)
+		return errors.Wrap(err, "AssociateEipToInstance")
+	}
+	s.eventf("SuccessfulAssociateEIP", "Associated EIP %s with SLB %s", eip.AllocationId, slbID)
+	target, err := s.vpc.WaitEIPStatus(eip.AllocationId, infrav1.EIPInUse)
+	if err != nil {
+		return errors.Wrap(err, "WaitEIPStatus")
+	}
+	target.DeepCopyInto(eip)
+	return nil
+}
+
+// verifyAPIServerAccess sets up the API server access added to the spec after the cluster became ready,
+// then checks the external SLB or EIP of a private cluster, the IPv6 SLB and the PrivateZone record and
+// republishes the API endpoints. A detached EIP is re-associated when auto-repair is on; a missing SLB
+// is only reported.
+func (s *ClusterProcessor) verifyAPIServerAccess() error {
+	network := &s.alicloudCluster.Status.Network
+
+	if s.apiServerAccessPending() {
+		if _, err := s.reconcileAPIServerAccess(); err != nil {
+			return errors.Wrap(err, "reconcileAPIServerAccess")
+		}
+	}
+
+	if err := s.verifyAccessSLB(s.externalSLBSpec(), &network.ExternalSLB, infrav1.ExternalLoadBalancerReadyCondition, "external"); err != nil {
+		return err
+	}
+	if err := s.verifyAccessSLB(s.ipv6SLBSpec(), &network.IPv6SLB, infrav1.IPv6LoadBalancerReadyCondition, "IPv6"); err != nil {
+		return err
+	}
+	if err := s.reconcilePrivateZone(); err != nil {
+		return errors.Wrap(err, "reconcilePrivateZone")
+	}
+	if err := s.publishAPIEndpoints(); err != nil {
+		return err
+	}
+
+	eip := &network.ExternalEIP
+	if len(eip.AllocationId) == 0 {
+		return nil
+	}
+	target, err := s.vpc.DescribeEIP(eip.AllocationId)
+	if err != nil {
+		return errors.Wrap(err, "DescribeEIP")
+	}
+	if target == nil {
+		s.markDrifted(infrav1.ExternalEIPReadyCondition, "external EIP %s no longer exists", eip.AllocationId)
+		return nil
+	}
+	target.DeepCopyInto(eip)
+	if eip.Status == infrav1.EIPInUse && eip.InstanceId == network.SLB.LoadBalancerId {
+		s.markAvailable(infrav1.ExternalEIPReadyCondition)
+		return nil
+	}
+	if eip.Status != infrav1.EIPAvailable || !s.autoRepair() {
+		s.markDrifted(infrav1.ExternalEIPReadyCondition, "external EIP %s is %s and no longer associated with SLB %s",
+			eip.AllocationId, eip.Status, network.SLB.LoadBalancerId)
+		return nil
+	}
+	if err := s.reconcileExternalEIP(); err != nil {
+		return errors.Wrap(err, "reconcileExternalEIP")
+	}
+	s.markRepaired(infrav1.ExternalEIPReadyCondition, "Re-associated EIP %s with SLB %s", eip.AllocationId, network.SLB.LoadBalancerId)
+	return nil
+}
+
+// verifyAccessSLB reports an additional SLB that is gone or no longer active, and checks its TCP listener.
+// A listener that never came up is completed; one that went missing after it was verified is restored
+// only when auto-repair is on, like the listener of the apiserver SLB.
+func (s *ClusterProcessor) verifyAccessSLB(spec infrav1.SLBSpec, status *infrav1.SLB, cond infrav1.ConditionType, kind string) error {
+	id := status.LoadBalancerId
+	if len(id) == 0 {
+		return nil
+	}
+	target, err := s.slb.Describe(id)
+	if err != nil {
+		return errors.Wrap(err, "Describe")
+	}
+	switch {
+	case target == nil:
+		s.markDrifted(cond, "%s SLB %s no longer exists", kind, id)
+		return nil
+	case target.LoadBalancerStatus != infrav1.SLBActive:
+		s.markDrifted(cond, "%s SLB %s is %s", kind, id, target.LoadBalancerStatus)
+		return nil
+	}
+
+	verified := s.alicloudCluster.Status.Conditions.Get(cond) != nil
+	if verified && !s.autoRepair() {
+		listener, err := s.slb.DescribeListenerStatus(id)
+		if err != nil {
+			return errors.Wrap(err, "DescribeListenerStatus")
+		}
+		if listener != "starting" && listener != "running" {
+			s.markDrifted(cond, "TCP listener on %s SLB %s is missing or %s", kind, id, listener)
+			return nil
+		}
+		s.markAvailable(cond)
+		return nil
+	}
+
+	changed, err := s.reconcileAccessListener(spec, status)
+	if err != nil {
+		return errors.Wrap(err, "reconcileAccessListener")
+	}
+	if changed && verified {
+		s.markRepaired(cond, "Restarted TCP listener on %s SLB %s", kind, id)
+		return nil
+	}
+	s.markAvailable(cond)
+	return nil
+}
+
+// deleteAPIServerAccess releases the PrivateZone record, the external EIP and SLB of a private cluster and the
+// IPv6 SLB if the provider created them.
+func (s *ClusterProcessor) deleteAPIServerAccess() (reconcile.Result, error) {
+	s.Info("deleteAPIServerAccess")
+	network := &s.alicloudCluster.Status.Network
+
+	if err := s.deletePrivateZone(); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "deletePrivateZone")
+	}
+
+	if id := network.ExternalEIP.AllocationId; len(id) > 0 {
+		target, err := s.vpc.DescribeEIP(id)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "DescribeEIP")
+		}
+		if target != nil {
+			if target.Status == infrav1.EIPInUse {
+				if err := s.vpc.UnassociateEipToInstance(id, target.InstanceId, aliyun.EipInstanceTypeSlb); err != nil {
+					s.warningf("FailedUnassociateEIP", err, "Failed to unassociate EIP %s from SLB %s", id, target.InstanceId)
+					return reconcile.Result{}, errors.Wrap(err, "UnassociateEipToInstance")
+				}
+				if _, err := s.vpc.WaitEIPStatus(id, infrav1.EIPAvailable); err != nil {
+					return reconcile.Result{}, errors.Wrap(err, "WaitEIPStatus Available")
+				}
+			}
+			if s.owns(id) {
+				if err := s.vpc.DeleteEIP(id); err != nil {
+					s.warningf("FailedDeleteEIP", err, "Failed to delete EIP %s", id)
+					return reconcile.Result{}, errors.Wrap(err, "DeleteEIP")
+				}
+				s.eventf("SuccessfulDeleteEIP", "Deleted EIP %s", id)
+			}
+		}
+		s.disown(id)
+		network.ExternalEIP = infrav1.EIP{}
+	}
+
+	if err := s.deleteAccessSLB(&network.ExternalSLB, "external"); err != nil {
+		return reconcile.Result{}, err
+	}
+	if err := s.deleteAccessSLB(&network.IPv6SLB, "IPv6"); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// deleteAccessSLB deletes an additional SLB if the provider created it and forgets it either way.
+func (s *ClusterProcessor) deleteAccessSLB(status *infrav1.SLB, kind string) error {
+	id := status.LoadBalancerId
+	if len(id) == 0 {
+		return nil
+	}
+	target, err := s.slb.Describe(id)
+	if err != nil {
+		return errors.Wrap(err, "Describe")
+	}
+	if target != nil && s.owns(id) {
+		if err := s.slb.Delete(id); err != nil {
+			s.warningf("FailedDeleteSLB", err, "Failed to delete %s SLB %s", kind, id)
+			return errors.Wrap(err, "Delete")
+		}
+		s.eventf("SuccessfulDeleteSLB", "Deleted %s SLB %s", kind, id)
+		if err := retry.Try(retry.DefaultBackOf, func() error {
+			target, err := s.slb.Describe(id)
+			if err != nil {
+				return err
+			}
+			if target != nil {
+				return retry.ErrRetry
+			}
+			return nil
+		}); err != nil {
+			return errors.Wrap(err, "wait deleted")
+		}
+	}
+	s.disown(id)
+	s.disown(status.VServerGroupId)
+	*status = infrav1.SLB{}
+	return nil
+}
@@ -0,0 +1,317 @@
+package controllers
+
+import (
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-alicloud/api/v1alpha2"
+	"sigs.k8s.io/cluster-api-provider-alicloud/pkg"
+	"sigs.k8s.io/cluster-api-provider-alicloud/pkg/aliyun"
+	"sigs.k8s.io/cluster-api-provider-alicloud/pkg/aliyun/retry"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	bastionSecurityGroupRole = "bastion"
+
+	defaultBastionInstanceType = "ecs.t5-lc1m1.small"
+)
+
+// bastionSpec returns Spec.Bastion with defaults filled in.
+func (s *ClusterProcessor) bastionSpec() infrav1.BastionSpec {
+	var spec infrav1.BastionSpec
+	if s.alicloudCluster.Spec.Bastion != nil {
+		s.alicloudCluster.Spec.Bastion.DeepCopyInto(&spec)
+	}
+	if len(spec.InstanceName) == 0 {
+		spec.InstanceName = s.cluster.Name + "-bastion"
+	}
+	if len(spec.InstanceType) == 0 {
+		spec.InstanceType = defaultBastionInstanceType
+	}
+	if len(spec.ImageId) == 0 {
+		spec.ImageId = DefaultOSImageId
+	}
+	if len(spec.KeyPairName) == 0 {
+		spec.KeyPairName = pkg.DefaultSSHKeyName
+	}
+	if len(spec.SecurityGroup.SecurityGroupName) == 0 {
+		spec.SecurityGroup.SecurityGroupName = s.cluster.Name + "-bastion"
+	}
+	return spec
+}
+
+func (s *ClusterProcessor) bastionEnabled() bool {
+	return s.alicloudCluster.Spec.Bastion != nil
+}
+
+// bastionSecurityGroup opens SSH on the bastion to the allowed CIDRs only.
+func (s *ClusterProcessor) bastionSecurityGroup() securityGroupScope {
+	spec := s.bastionSpec()
+
+	rules := specRules(spec.SecurityGroup.Rules)
+	for _, cidr := range spec.AllowedCIDRs {
+		rules = append(rules, cidrRule("tcp", "22/22", cidr, "bastion ssh"))
+	}
+	return securityGroupScope{
+		role:      bastionSecurityGroupRole,
+		spec:      spec.SecurityGroup,
+		status:    &s.alicloudCluster.Status.Bastion.SecurityGroup,
+		condition: infrav1.BastionSecurityGroupReadyCondition,
+		rules:     rules,
+	}
+}
+
+// bastionRules lets the bastion reach SSH on cluster machines.
+func (s *ClusterProcessor) bastionRules() []infrav1.SecurityGroupRuleSpec {
+	id := s.alicloudCluster.Status.Bastion.SecurityGroup.SecurityGroupId
+	if !s.bastionEnabled() || len(id) == 0 {
+		return nil
+	}
+	return []infrav1.SecurityGroupRuleSpec{groupRule("tcp", "22/22", id, "ssh from bastion")}
+}
+
+// reconcileBastion provisions the bastion host while Spec.Bastion is set and releases it once the
+// section is removed. It runs on every resync, so a bastion can be added to or removed from a ready cluster.
+func (s *ClusterProcessor) reconcileBastion() (reconcile.Result, error) {
+	status := &s.alicloudCluster.Status.Bastion
+	if !s.bastionEnabled() {
+		if len(status.InstanceId) == 0 && len(status.EIP.AllocationId) == 0 && len(status.SecurityGroup.SecurityGroupId) == 0 {
+			return reconcile.Result{}, nil
+		}
+		return s.disableBastion()
+	}
+
+	s.Info("reconcileBastion")
+
+	sg := s.bastionSecurityGroup()
+	if rs, err := s.reconcileSecurityGroupOf(sg); err != nil {
+		return rs, errors.Wrap(err, "reconcileSecurityGroup")
+	}
+	if rs, err := s.reconcileSecurityGroupRulesOf(sg); err != nil {
+		return rs, errors.Wrap(err, "reconcileSecurityGroupRules")
+	}
+
+	if err := s.reconcileBastionInstance(); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "reconcileBastionInstance")
+	}
+	if len(status.InstanceId) == 0 || status.Status != "Running" {
+		return reconcile.Result{}, nil
+	}
+	if err := s.reconcileBastionEIP(); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "reconcileBastionEIP")
+	}
+	if status.EIP.Status != infrav1.EIPInUse || status.EIP.InstanceId != status.InstanceId {
+		return reconcile.Result{}, nil
+	}
+
+	status.PublicIpAddress = status.EIP.IpAddress
+	if !s.alicloudCluster.Status.Conditions.IsTrue(infrav1.BastionReadyCondition) {
+		s.eventf("BastionReady", "Bastion %s is reachable at %s", status.InstanceId, status.PublicIpAddress)
+	}
+	s.markAvailable(infrav1.BastionReadyCondition)
+	s.Info("reconcileBastion success", "status", status)
+	return reconcile.Result{}, nil
+}
+
+func (s *ClusterProcessor) reconcileBastionInstance() error {
+	status := &s.alicloudCluster.Status.Bastion
+
+	if len(status.InstanceId) > 0 {
+		instance, err := s.bastion.Describe(status.InstanceId)
+		if err != nil {
+			return errors.Wrap(err, "Describe")
+		}
+		if instance != nil {
+			status.FillFrom(instance)
+			if status.Status != "Running" {
+				s.markDrifted(infrav1.BastionReadyCondition, "bastion %s is %s", status.InstanceId, status.Status)
+			}
+			return nil
+		}
+		if !s.autoRepair() {
+			s.markDrifted(infrav1.BastionReadyCondition, "bastion %s no longer exists", status.InstanceId)
+			return nil
+		}
+		// nothing refers to the bastion by ID, so a missing one is simply replaced
+		s.Info("bastion instance gone, recreating", "id", status.InstanceId)
+		s.disown(status.InstanceId)
+		status.InstanceId = ""
+		status.Status = ""
+	}
+
+	spec := s.bastionSpec()
+	network := &s.alicloudCluster.Status.Network
+	id, err := s.bastion.Create(spec, s.alicloudCluster.Spec.ZoneId, network.VSwitch.VSwitchId, status.SecurityGroup.SecurityGroupId)
+	if err != nil {
+		s.warningf("FailedCreateBastion", err, "Failed to launch bastion instance")
+		return errors.Wrap(err, "Create")
+	}
+	s.eventf("SuccessfulCreateBastion", "Launched bastion instance %s", id)
+	s.own(id)
+	status.InstanceId = id
+	_ = s.patch()
+
+	instance, err := s.bastion.WaitRunning(id)
+	if err != nil {
+		return errors.Wrapf(err, "WaitRunning %v", id)
+	}
+	status.FillFrom(instance)
+	return nil
+}
+
+func (s *ClusterProcessor) reconcileBastionEIP() error {
+	status := &s.alicloudCluster.Status.Bastion
+	eip := &status.EIP
+
+	if len(eip.AllocationId) > 0 {
+		target, err := s.vpc.DescribeEIP(eip.AllocationId)
+		if err != nil {
+			return errors.Wrap(err, "DescribeEIP")
+		}
+		if target == nil {
+			if !s.autoRepair() {
+				s.markDrifted(infrav1.BastionReadyCondition, "bastion EIP %s no longer exists", eip.AllocationId)
+				return nil
+			}
+			s.disown(eip.AllocationId)
+			*eip = infrav1.EIP{}
+		} else {
+			target.DeepCopyInto(eip)
+		}
+	}
+
+	if len(eip.AllocationId) == 0 {
+		spec := s.bastionSpec()
+		id, err := s.vpc.CreateEIP(spec.EIP, s.alicloudCluster.Status.Network.VPC.VpcId)
+		if err != nil {
+			s.warningf("FailedCreateEIP", err, "Failed to allocate EIP for bastion")
+			return errors.Wrap(err, "CreateEIP")
+		}
+		s.eventf("SuccessfulCreateEIP", "Allocated EIP %s for bastion", id)
+		s.own(id)
+		target, err := s.vpc.WaitEIPStatus(id, infrav1.EIPAvailable)
+		if err != nil {
+			return errors.Wrapf(err, "WaitEIPStatus %v", id)
+		}
+		target.DeepCopyInto(eip)
+		_ = s.patch()
+	}
+
+	if eip.Status == infrav1.EIPInUse && eip.InstanceId == status.InstanceId {
+		return nil
+	}
+	if eip.Status != infrav1.EIPAvailable {
+		s.markDrifted(infrav1.BastionReadyCondition, "bastion EIP %s is %s and associated with %s",
+			eip.AllocationId, eip.Status, eip.InstanceId)
+		return nil
+	}
+
+	if err := s.vpc.AssociateEipToInstance(eip.AllocationId, status.InstanceId, aliyun.EipInstanceTypeEcs); err != nil {
+		s.warningf("FailedAssociateEIP", err, "Failed to associate EIP %s with bastion %s", eip.AllocationId, status.InstanceId)
+		return errors.Wrap(err, "AssociateEipToInstance")
+	}
+	s.eventf("SuccessfulAssociateEIP", "Associated EIP %s with bastion %s", eip.AllocationId, status.InstanceId)
+	target, err := s.vpc.WaitEIPStatus(eip.AllocationId, infrav1.EIPInUse)
+	if err != nil {
+		return errors.Wrap(err, "WaitEIPStatus")
+	}
+	target.DeepCopyInto(eip)
+	return nil
+}
+
+// disableBastion releases the bastion after Spec.Bastion was removed, dropping the SSH rules
+// that reference its security group before deleting the group.
+func (s *ClusterProcessor) disableBastion() (reconcile.Result, error) {
+	s.Info("disableBastion")
+
+	if rs, err := s.deleteBastion(); err != nil {
+		return rs, errors.Wrap(err, "deleteBastion")
+	}
+
+	sg := s.bastionSecurityGroup()
+	for _, other := range s.securityGroups() {
+		if other.role == bastionSecurityGroupRole {
+			continue
+		}
+		if _, err := s.reconcileSecurityGroupRulesOf(other); err != nil {
+			return reconcile.Result{}, errors.Wrapf(err, "reconcileSecurityGroupRules %v", other.role)
+		}
+	}
+	if rs, err := s.deleteSecurityGroupOf(sg); err != nil {
+		return rs, errors.Wrap(err, "deleteSecurityGroup")
+	}
+
+	s.disown(sg.status.SecurityGroupId)
+	s.alicloudCluster.Status.Bastion = infrav1.Bastion{}
+	s.alicloudCluster.Status.Conditions.Remove(infrav1.BastionReadyCondition)
+	s.alicloudCluster.Status.Conditions.Remove(infrav1.BastionSecurityGroupReadyCondition)
+	return reconcile.Result{}, nil
+}
+
+// deleteBastion releases the bastion instance and its EIP. The security group is left to
+// deleteSecurityGroup, which also revokes the rules of other groups that reference it.
+func (s *ClusterProcessor) deleteBastion() (reconcile.Result, error) {
+	s.Info("deleteBastion")
+	status := &s.alicloudCluster.Status.Bastion
+
+	if id := status.EIP.AllocationId; len(id) > 0 {
+		target, err := s.vpc.DescribeEIP(id)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "DescribeEIP")
+		}
+		if target != nil {
+			if target.Status == infrav1.EIPInUse {
+				if err := s.vpc.UnassociateEipToInstance(id, target.InstanceId, aliyun.EipInstanceTypeEcs); err != nil {
+					s.warningf("FailedUnassociateEIP", err, "Failed to unassociate EIP %s from bastion %s", id, target.InstanceId)
+					return reconcile.Result{}, errors.Wrap(err, "UnassociateEipToInstance")
+				}
+				if _, err := s.vpc.WaitEIPStatus(id, infrav1.EIPAvailable); err != nil {
+					return reconcile.Result{}, errors.Wrap(err, "WaitEIPStatus Available")
+				}
+			}
+			if s.owns(id) {
+				if err := s.vpc.DeleteEIP(id); err != nil {
+					s.warningf("FailedDeleteEIP", err, "Failed to delete bastion EIP %s", id)
+					return reconcile.Result{}, errors.Wrap(err, "DeleteEIP")
+				}
+				s.eventf("SuccessfulDeleteEIP", "Deleted bastion EIP %s", id)
+			}
+		}
+		s.disown(id)
+		status.EIP = infrav1.EIP{}
+		status.PublicIpAddress = ""
+	}
+
+	if id := status.InstanceId; len(id) > 0 {
+		target, err := s.bastion.Describe(id)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "Describe")
+		}
+		if target != nil && s.owns(id) {
+			if err := s.bastion.Delete(id); err != nil {
+				s.warningf("FailedDeleteBastion", err, "Failed to delete bastion instance %s", id)
+				return reconcile.Result{}, errors.Wrap(err, "Delete")
+			}
+			s.eventf("SuccessfulDeleteBastion", "Deleted bastion instance %s", id)
+
+			// the security group cannot be deleted while the instance still uses it
+			if err := retry.Try(retry.DefaultBackOf, func() error {
+				target, err := s.bastion.Describe(id)
+				if err != nil {
+					return err
+				}
+				if target != nil {
+					return retry.ErrRetry
+				}
+				return nil
+			}); err != nil {
+				return reconcile.Result{}, errors.Wrap(err, "wait deleted")
+			}
+		}
+		s.disown(id)
+		status.InstanceId = ""
+		status.Status = ""
+		status.PrivateIpAddress = ""
+	}
+
+	return reconcile.Result{}, nil
+}
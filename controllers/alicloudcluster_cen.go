@@ -0,0 +1,166 @@
+package controllers
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-alicloud/api/v1alpha2"
+	"sigs.k8s.io/cluster-api-provider-alicloud/pkg/aliyun"
+)
+
+// reconcileCEN attaches the cluster VPC to the CEN instance of Spec.Network.CEN and publishes the listed
+// route entries. Removing Spec.Network.CEN only takes effect when the cluster is deleted.
+func (s *ClusterProcessor) reconcileCEN() error {
+	spec := s.alicloudCluster.Spec.Network.CEN
+	if spec == nil {
+		return nil
+	}
+
+	s.Info("reconcileCEN")
+
+	status := &s.alicloudCluster.Status.Network.CEN
+	vpcID := s.alicloudCluster.Status.Network.VPC.VpcId
+	if len(status.CenId) > 0 && status.CenId != spec.CenId {
+		if err := s.deleteCEN(); err != nil {
+			return errors.Wrap(err, "deleteCEN")
+		}
+	}
+
+	var drifted, repaired []string
+	attachment, err := s.cen.DescribeAttachment(spec.CenId, vpcID)
+	if err != nil {
+		return errors.Wrap(err, "DescribeAttachment")
+	}
+	if attachment == nil {
+		if len(status.CenId) > 0 && !s.autoRepair() {
+			s.markDrifted(infrav1.CENAttachedCondition, "VPC %s is no longer attached to CEN %s", vpcID, spec.CenId)
+			return nil
+		}
+		if err := s.cen.Attach(spec.CenId, vpcID); err != nil {
+			s.warningf("FailedAttachCEN", err, "Failed to attach VPC %s to CEN %s", vpcID, spec.CenId)
+			return errors.Wrap(err, "Attach")
+		}
+		if len(status.CenId) > 0 {
+			repaired = append(repaired, "Attached VPC "+vpcID+" to CEN "+spec.CenId+" again")
+		} else {
+			s.eventf("SuccessfulAttachCEN", "Attached VPC %s to CEN %s", vpcID, spec.CenId)
+		}
+	}
+	if err := s.cen.WaitAttached(spec.CenId, vpcID); err != nil {
+		return errors.Wrap(err, "WaitAttached")
+	}
+	status.CenId = spec.CenId
+	status.Status = aliyun.CenAttached
+	_ = s.patch()
+
+	if len(spec.PublishRoutes) > 0 || len(status.PublishedRoutes) > 0 {
+		if err := s.reconcileCENRoutes(spec, &drifted, &repaired); err != nil {
+			return errors.Wrap(err, "reconcileCENRoutes")
+		}
+	}
+
+	s.Info("reconcileCEN success", "status", status)
+	switch {
+	case len(drifted) > 0:
+		s.markDrifted(infrav1.CENAttachedCondition, "%s", strings.Join(drifted, "; "))
+		return nil
+	case len(repaired) > 0:
+		s.markRepaired(infrav1.CENAttachedCondition, "%s", strings.Join(repaired, "; "))
+		return nil
+	}
+	s.markAvailable(infrav1.CENAttachedCondition)
+	return nil
+}
+
+// reconcileCENRoutes publishes the route entries listed in the spec and withdraws those removed from it.
+func (s *ClusterProcessor) reconcileCENRoutes(spec *infrav1.CENSpec, drifted, repaired *[]string) error {
+	status := &s.alicloudCluster.Status.Network.CEN
+	vpcID := s.alicloudCluster.Status.Network.VPC.VpcId
+
+	if len(status.RouteTableId) == 0 {
+		tableID := s.alicloudCluster.Status.Network.RouteTable.RouteTableId
+		if len(tableID) == 0 {
+			table, err := s.routeTable.DescribeSystem(s.alicloudCluster.Status.Network.VPC.VRouterId)
+			if err != nil {
+				return errors.Wrap(err, "DescribeSystem")
+			}
+			if table == nil {
+				return errors.Errorf("system route table of %s not found", s.alicloudCluster.Status.Network.VPC.VRouterId)
+			}
+			tableID = table.RouteTableId
+		}
+		status.RouteTableId = tableID
+	}
+
+	published, err := s.cen.DescribePublishedRoutes(status.CenId, vpcID, status.RouteTableId)
+	if err != nil {
+		return errors.Wrap(err, "DescribePublishedRoutes")
+	}
+
+	for _, cidr := range status.PublishedRoutes {
+		if contains(spec.PublishRoutes, cidr) {
+			continue
+		}
+		if published[cidr] == aliyun.CenPublished {
+			if err := s.cen.WithdrawRoute(status.CenId, vpcID, status.RouteTableId, cidr); err != nil {
+				s.warningf("FailedWithdrawCENRoute", err, "Failed to withdraw route %s from CEN %s", cidr, status.CenId)
+				return errors.Wrap(err, "WithdrawRoute")
+			}
+			s.eventf("SuccessfulWithdrawCENRoute", "Withdrew route %s from CEN %s", cidr, status.CenId)
+		}
+		status.PublishedRoutes = filter(status.PublishedRoutes, cidr)
+	}
+
+	for _, cidr := range spec.PublishRoutes {
+		recorded := contains(status.PublishedRoutes, cidr)
+		if published[cidr] == aliyun.CenPublished {
+			if !recorded {
+				status.PublishedRoutes = append(status.PublishedRoutes, cidr)
+			}
+			continue
+		}
+		if recorded && !s.autoRepair() {
+			*drifted = append(*drifted, "Route "+cidr+" is no longer published to CEN "+status.CenId)
+			continue
+		}
+		if err := s.cen.PublishRoute(status.CenId, vpcID, status.RouteTableId, cidr); err != nil {
+			s.warningf("FailedPublishCENRoute", err, "Failed to publish route %s to CEN %s", cidr, status.CenId)
+			return errors.Wrap(err, "PublishRoute")
+		}
+		if recorded {
+			*repaired = append(*repaired, "Published route "+cidr+" to CEN "+status.CenId+" again")
+		} else {
+			s.eventf("SuccessfulPublishCENRoute", "Published route %s to CEN %s", cidr, status.CenId)
+			status.PublishedRoutes = append(status.PublishedRoutes, cidr)
+		}
+	}
+	return nil
+}
+
+// deleteCEN withdraws the routes the provider published and detaches the cluster VPC from the CEN instance.
+func (s *ClusterProcessor) deleteCEN() error {
+	status := &s.alicloudCluster.Status.Network.CEN
+	if len(status.CenId) == 0 {
+		return nil
+	}
+
+	s.Info("deleteCEN")
+
+	vpcID := s.alicloudCluster.Status.Network.VPC.VpcId
+	for _, cidr := range status.PublishedRoutes {
+		if err := s.cen.WithdrawRoute(status.CenId, vpcID, status.RouteTableId, cidr); err != nil {
+			s.warningf("FailedWithdrawCENRoute", err, "Failed to withdraw route %s from CEN %s", cidr, status.CenId)
+			return errors.Wrap(err, "WithdrawRoute")
+		}
+	}
+	status.PublishedRoutes = nil
+
+	if err := s.cen.Detach(status.CenId, vpcID); err != nil {
+		s.warningf("FailedDetachCEN", err, "Failed to detach VPC %s from CEN %s", vpcID, status.CenId)
+		return errors.Wrap(err, "Detach")
+	}
+	s.eventf("SuccessfulDetachCEN", "Detached VPC %s from CEN %s", vpcID, status.CenId)
+
+	*status = infrav1.CEN{}
+	return nil
+}
@@ -22,24 +22,45 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha2"
 	"sigs.k8s.io/cluster-api/util"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-alicloud/api/v1alpha2"
+	"sigs.k8s.io/cluster-api-provider-alicloud/pkg/aliyun/metrics"
 )
 
 // AlicloudClusterReconciler reconciles a AlicloudCluster object
 type AlicloudClusterReconciler struct {
 	client.Client
-	Log    logr.Logger
-	Scheme *runtime.Scheme
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// ResyncPeriod is how often the network of a ready cluster is verified against the cloud.
+	// Zero disables periodic resync.
+	ResyncPeriod time.Duration
+
+	// MaxConcurrentReconciles is the number of AlicloudClusters reconciled in parallel. Defaults to 1.
+	MaxConcurrentReconciles int
+
+	// ClusterSelector restricts the reconciler to the clusters whose CAPI Cluster labels match, so that
+	// clusters can be sharded across provider instances. Nil selects every cluster.
+	ClusterSelector labels.Selector
 }
 
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=alicloudclusters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=alicloudclusters/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;machines,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;patch
 
 func (r *AlicloudClusterReconciler) Reconcile(req ctrl.Request) (_ ctrl.Result, reterr error) {
 	ctx := context.Background()
@@ -58,11 +79,16 @@ func (r *AlicloudClusterReconciler) Reconcile(req ctrl.Request) (_ ctrl.Result,
 		return reconcile.Result{}, errors.Wrap(err, "GetOwnerCluster")
 	}
 	if cluster == nil {
+		// Setting the OwnerRef updates the AlicloudCluster, which triggers another reconcile.
 		logger.Info("Cluster Controller has not yet set OwnerRef")
-		return reconcile.Result{RequeueAfter: time.Second * 5}, nil
+		return reconcile.Result{}, nil
+	}
+	if !selectsCluster(r.ClusterSelector, cluster) {
+		logger.V(1).Info("Cluster not selected by this instance", "cluster", cluster.Name)
+		return reconcile.Result{}, nil
 	}
 
-	processor, err := NewClusterProcessor(logger, alicloudCluster.Spec.RegionId, r.Client, cluster, alicloudCluster)
+	processor, err := NewClusterProcessor(logger, alicloudCluster.Spec.RegionId, r.Client, r.Recorder, cluster, alicloudCluster)
 	if err != nil {
 		logger.Error(err, "NewClusterProcessor error")
 		return reconcile.Result{}, errors.Wrap(err, "NewClusterProcessor")
@@ -77,31 +103,48 @@ func (r *AlicloudClusterReconciler) Reconcile(req ctrl.Request) (_ ctrl.Result,
 
 	// Handle deleted clusters
 	if !alicloudCluster.DeletionTimestamp.IsZero() {
-		if ret, err := processor.ReconcileDelete(); err != nil {
+		ret, err := processor.ReconcileDelete()
+		if err != nil {
 			logger.Error(err, "ReconcileDelete error")
 			return ret, errors.Wrap(err, "ReconcileDelete")
 		}
-		return ctrl.Result{}, nil
+		return ret, nil
 	}
 
 	// Handle non-deleted clusters
 	if ret, err := processor.ReconcileNormal(); err != nil {
+		// Ready is left untouched: a ready cluster whose network verification failed keeps serving
+		// and is retried, while drift itself is reported through conditions.
 		alicloudCluster.Status.Reason = err.Error()
-		alicloudCluster.Status.Ready = false
 
 		logger.Error(err, "ReconcileNormal error")
 		return ret, errors.Wrap(err, "ReconcileNormal")
 	}
 
+	if !alicloudCluster.Status.Ready {
+		metrics.ObserveClusterReady(time.Since(alicloudCluster.CreationTimestamp.Time))
+	}
 	alicloudCluster.Status.Message = "success"
 	alicloudCluster.Status.Reason = ""
 	alicloudCluster.Status.Ready = true
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: r.ResyncPeriod}, nil
 }
 
 func (r *AlicloudClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&infrav1.AlicloudCluster{}).
+		Watches(
+			&source.Kind{Type: &clusterv1.Cluster{}},
+			&handler.EnqueueRequestsFromMapFunc{
+				ToRequests: util.ClusterToInfrastructureMapFunc(infrav1.GroupVersion.WithKind("AlicloudCluster")),
+			},
+		).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
 		Complete(r)
 }
+
+// selectsCluster reports whether the cluster labels match selector. A nil selector matches every cluster.
+func selectsCluster(selector labels.Selector, cluster *clusterv1.Cluster) bool {
+	return selector == nil || selector.Matches(labels.Set(cluster.Labels))
+}
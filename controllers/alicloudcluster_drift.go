@@ -0,0 +1,287 @@
+package controllers
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-alicloud/api/v1alpha2"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// verifyNetwork compares every network component recorded in Status.Network with the cloud.
+// Drift is repaired in place when Spec.Network.AutoRepair is set and reported through conditions otherwise.
+// Components that everything else depends on (VPC, VSwitch, NAT gateway, SLB, security group) are never
+// recreated, since a new ID would orphan the instances and endpoints using the old one.
+//
+// Spec sections added after the cluster became ready are set up here as well: route table, network ACL,
+// CEN, NAT EIP pool, bandwidth package and entries, API server access, PrivateZone, security groups and
+// their rules, and the bastion. Changes to the VPC, VSwitch, NAT gateway and SLB specs are ignored once
+// the cluster is ready.
+func (s *ClusterProcessor) verifyNetwork() (reconcile.Result, error) {
+	s.Info("verifyNetwork")
+
+	if err := s.verifyVPC(); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "verifyVPC")
+	}
+	if err := s.verifyVSwitch(); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "verifyVSwitch")
+	}
+	if err := s.reconcileRouteTable(); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "reconcileRouteTable")
+	}
+	if err := s.reconcileNetworkAcl(); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "reconcileNetworkAcl")
+	}
+	if err := s.reconcileCEN(); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "reconcileCEN")
+	}
+	if err := s.verifyNat(); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "verifyNat")
+	}
+	if err := s.verifySLB(); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "verifySLB")
+	}
+	if _, err := s.reconcileBastion(); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "reconcileBastion")
+	}
+	if err := s.verifyAPIServerAccess(); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "verifyAPIServerAccess")
+	}
+	if err := s.verifySecurityGroup(); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "verifySecurityGroup")
+	}
+
+	now := metav1.Now()
+	s.alicloudCluster.Status.LastSyncTime = &now
+
+	s.Info("verifyNetwork success")
+	return reconcile.Result{}, nil
+}
+
+func (s *ClusterProcessor) autoRepair() bool {
+	return s.alicloudCluster.Spec.Network.AutoRepair
+}
+
+func (s *ClusterProcessor) markAvailable(t infrav1.ConditionType) {
+	s.alicloudCluster.Status.Conditions.MarkTrue(t, infrav1.ResourceAvailableReason, "")
+}
+
+// markDrifted flags t as drifted and emits a warning when the drift is new.
+func (s *ClusterProcessor) markDrifted(t infrav1.ConditionType, messageFmt string, args ...interface{}) {
+	msg := fmt.Sprintf(messageFmt, args...)
+	conds := &s.alicloudCluster.Status.Conditions
+	if c := conds.Get(t); c == nil || c.Status != corev1.ConditionFalse || c.Message != msg {
+		s.Info("network drift", "condition", t, "message", msg)
+		s.recorder.Event(s.alicloudCluster, corev1.EventTypeWarning, "NetworkDrift", msg)
+	}
+	conds.MarkFalse(t, infrav1.ResourceDriftedReason, msg)
+}
+
+func (s *ClusterProcessor) markRepaired(t infrav1.ConditionType, messageFmt string, args ...interface{}) {
+	msg := fmt.Sprintf(messageFmt, args...)
+	s.Info("network repaired", "condition", t, "message", msg)
+	s.eventf("NetworkRepaired", "%s", msg)
+	s.alicloudCluster.Status.Conditions.MarkTrue(t, infrav1.ResourceRepairedReason, msg)
+}
+
+func (s *ClusterProcessor) verifyVPC() error {
+	id := s.alicloudCluster.Status.Network.VPC.VpcId
+	if len(id) == 0 {
+		return nil
+	}
+
+	target, err := s.vpc.Describe(id)
+	if err != nil {
+		return errors.Wrap(err, "Describe")
+	}
+	if target == nil {
+		s.markDrifted(infrav1.VPCReadyCondition, "VPC %s no longer exists", id)
+		return nil
+	}
+	s.markAvailable(infrav1.VPCReadyCondition)
+	return nil
+}
+
+func (s *ClusterProcessor) verifyVSwitch() error {
+	id := s.alicloudCluster.Status.Network.VSwitch.VSwitchId
+	if len(id) == 0 {
+		return nil
+	}
+
+	target, err := s.vswitch.Describe(id)
+	if err != nil {
+		return errors.Wrap(err, "Describe")
+	}
+	if target == nil {
+		s.markDrifted(infrav1.VSwitchReadyCondition, "VSwitch %s no longer exists", id)
+		return nil
+	}
+	s.markAvailable(infrav1.VSwitchReadyCondition)
+	return nil
+}
+
+func (s *ClusterProcessor) verifyNat() error {
+	nat := &s.alicloudCluster.Status.Network.Nat
+	if len(nat.NatGateway.NatGatewayId) == 0 {
+		return nil
+	}
+
+	ngw, err := s.vpc.DescribeNatGateway(nat.NatGateway.NatGatewayId)
+	if err != nil {
+		return errors.Wrap(err, "DescribeNatGateway")
+	}
+	if ngw == nil {
+		s.markDrifted(infrav1.NatGatewayReadyCondition, "NAT gateway %s no longer exists", nat.NatGateway.NatGatewayId)
+		return nil
+	}
+	s.markAvailable(infrav1.NatGatewayReadyCondition)
+
+	if err := s.verifyEIP(); err != nil {
+		return errors.Wrap(err, "verifyEIP")
+	}
+	if !s.alicloudCluster.Status.Conditions.IsTrue(infrav1.EIPReadyCondition) {
+		// NAT entries cannot be restored without the EIP
+		return nil
+	}
+	if err := s.reconcileEIPPool(); err != nil {
+		return errors.Wrap(err, "reconcileEIPPool")
+	}
+	if err := s.reconcileBandwidthPackage(); err != nil {
+		return errors.Wrap(err, "reconcileBandwidthPackage")
+	}
+	return errors.Wrap(s.reconcileNatEntries(), "reconcileNatEntries")
+}
+
+func (s *ClusterProcessor) verifyEIP() error {
+	nat := &s.alicloudCluster.Status.Network.Nat
+	id := nat.EIP.AllocationId
+	if len(id) == 0 {
+		return nil
+	}
+
+	target, err := s.vpc.DescribeEIP(id)
+	if err != nil {
+		return errors.Wrap(err, "DescribeEIP")
+	}
+	if target == nil {
+		s.markDrifted(infrav1.EIPReadyCondition, "EIP %s no longer exists", id)
+		return nil
+	}
+	if target.Status == infrav1.EIPInUse && target.InstanceId == nat.NatGateway.NatGatewayId {
+		s.markAvailable(infrav1.EIPReadyCondition)
+		return nil
+	}
+	if target.Status != infrav1.EIPAvailable || !s.autoRepair() {
+		s.markDrifted(infrav1.EIPReadyCondition, "EIP %s is %s and no longer associated with NAT gateway %s",
+			id, target.Status, nat.NatGateway.NatGatewayId)
+		return nil
+	}
+
+	if err := s.vpc.AssociateEipToNatGateway(target, &nat.NatGateway); err != nil {
+		s.warningf("FailedAssociateEIP", err, "Failed to associate EIP %s with NAT gateway %s", id, nat.NatGateway.NatGatewayId)
+		return errors.Wrap(err, "AssociateEipToNatGateway")
+	}
+	target, err = s.vpc.WaitEIPStatus(id, infrav1.EIPInUse)
+	if err != nil {
+		return errors.Wrap(err, "WaitEIPStatus")
+	}
+	target.DeepCopyInto(&nat.EIP)
+	s.markRepaired(infrav1.EIPReadyCondition, "Re-associated EIP %s with NAT gateway %s", id, nat.NatGateway.NatGatewayId)
+	return nil
+}
+
+func (s *ClusterProcessor) verifySLB() error {
+	id := s.alicloudCluster.Status.Network.SLB.LoadBalancerId
+	if len(id) == 0 {
+		return nil
+	}
+
+	target, err := s.slb.Describe(id)
+	if err != nil {
+		return errors.Wrap(err, "Describe")
+	}
+	if target == nil {
+		s.markDrifted(infrav1.LoadBalancerReadyCondition, "SLB %s no longer exists", id)
+		return nil
+	}
+	if target.LoadBalancerStatus != infrav1.SLBActive {
+		s.markDrifted(infrav1.LoadBalancerReadyCondition, "SLB %s is %s", id, target.LoadBalancerStatus)
+	} else {
+		s.markAvailable(infrav1.LoadBalancerReadyCondition)
+	}
+
+	return errors.Wrap(s.verifyListener(), "verifyListener")
+}
+
+func (s *ClusterProcessor) verifyListener() error {
+	id := s.alicloudCluster.Status.Network.SLB.LoadBalancerId
+
+	status, err := s.slb.DescribeListenerStatus(id)
+	if err != nil {
+		return errors.Wrap(err, "DescribeListenerStatus")
+	}
+	if status == "starting" || status == "running" {
+		s.markAvailable(infrav1.ListenerReadyCondition)
+		return nil
+	}
+	if !s.autoRepair() {
+		if len(status) == 0 {
+			s.markDrifted(infrav1.ListenerReadyCondition, "TCP listener on SLB %s no longer exists", id)
+		} else {
+			s.markDrifted(infrav1.ListenerReadyCondition, "TCP listener on SLB %s is %s", id, status)
+		}
+		return nil
+	}
+
+	if len(status) == 0 {
+		vsgID := s.alicloudCluster.Status.Network.SLB.VServerGroupId
+		if err := s.slb.CreateTCPListener(s.slbSpec(), id, vsgID); err != nil {
+			s.warningf("FailedCreateListener", err, "Failed to create TCP listener on SLB %s", id)
+			return errors.Wrap(err, "CreateTCPListener")
+		}
+	}
+	if err := s.slb.StartListener(id); err != nil {
+		s.warningf("FailedStartListener", err, "Failed to start TCP listener on SLB %s", id)
+		return errors.Wrap(err, "StartListener")
+	}
+	s.markRepaired(infrav1.ListenerReadyCondition, "Restarted TCP listener on SLB %s", id)
+	return nil
+}
+
+func (s *ClusterProcessor) verifySecurityGroup() error {
+	// groups added to the spec after the cluster became ready, e.g. the control-plane and node groups,
+	// are created first since the rules of one group may reference another
+	for _, sg := range s.securityGroups() {
+		if len(sg.status.SecurityGroupId) > 0 {
+			continue
+		}
+		if _, err := s.reconcileSecurityGroupOf(sg); err != nil {
+			return errors.Wrapf(err, "reconcileSecurityGroup %v", sg.role)
+		}
+	}
+
+	for _, sg := range s.securityGroups() {
+		id := sg.status.SecurityGroupId
+		if len(id) == 0 {
+			continue
+		}
+
+		target, err := s.securityGroup.Describe(id)
+		if err != nil {
+			return errors.Wrap(err, "Describe")
+		}
+		if target == nil {
+			s.markDrifted(sg.condition, "%s security group %s no longer exists", sg.role, id)
+			continue
+		}
+		s.markAvailable(sg.condition)
+
+		// rules follow the spec on every resync; provider-managed rules deleted out-of-band are drift
+		if _, err := s.reconcileSecurityGroupRulesOf(sg); err != nil {
+			return errors.Wrapf(err, "reconcileSecurityGroupRules %v", sg.role)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-alicloud/api/v1alpha2"
+)
+
+func (s *ClusterProcessor) dualStack() bool {
+	return s.alicloudCluster.Spec.Network.DualStack
+}
+
+// vpcSpec returns Spec.Network.VPC with IPv6 enabled for a dual-stack cluster.
+func (s *ClusterProcessor) vpcSpec() infrav1.VPCSpec {
+	spec := s.alicloudCluster.Spec.Network.VPC
+	if s.dualStack() {
+		spec.EnableIpv6 = true
+	}
+	return spec
+}
+
+// vswitchSpec returns Spec.Network.VSwitch with an IPv6 block for a dual-stack cluster,
+// which defaults to the first /64 of the VPC IPv6 block.
+func (s *ClusterProcessor) vswitchSpec() infrav1.VSwitchSpec {
+	spec := s.alicloudCluster.Spec.Network.VSwitch
+	if s.dualStack() && len(spec.Ipv6CidrBlock) == 0 {
+		spec.Ipv6CidrBlock = "0"
+	}
+	return spec
+}
+
+// requireIPv6 fails a dual-stack cluster that adopts a VPC or VSwitch without an IPv6 block,
+// since IPv6 cannot be enabled on it afterwards by this provider.
+func (s *ClusterProcessor) requireIPv6(kind, id, ipv6CidrBlock string) error {
+	if !s.dualStack() || len(ipv6CidrBlock) > 0 {
+		return nil
+	}
+	return errors.Errorf("dual-stack cluster requires IPv6 on %s %s", kind, id)
+}
+
+// ipv6SLBSpec returns Spec.Network.IPv6SLB forced to an IPv6 internet SLB, the only kind Alibaba Cloud offers.
+func (s *ClusterProcessor) ipv6SLBSpec() infrav1.SLBSpec {
+	spec := s.alicloudCluster.Spec.Network.IPv6SLB
+	spec.AddressType = "internet"
+	spec.AddressIPVersion = "ipv6"
+	return spec
+}
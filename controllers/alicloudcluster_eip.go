@@ -0,0 +1,328 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-alicloud/api/v1alpha2"
+)
+
+// natEIPIDs returns the EIP of the NAT gateway followed by the EIP pool.
+func (s *ClusterProcessor) natEIPIDs() []string {
+	nat := &s.alicloudCluster.Status.Network.Nat
+	var ids []string
+	if len(nat.EIP.AllocationId) > 0 {
+		ids = append(ids, nat.EIP.AllocationId)
+	}
+	for _, eip := range nat.EIPs {
+		ids = append(ids, eip.AllocationId)
+	}
+	return ids
+}
+
+// reconcileEIPPool allocates or adopts the EIPs of Spec.Network.Nat.EIPs and binds them to the NAT gateway.
+// Pool EIPs that are no longer wanted are unbound, and released if the provider allocated them.
+func (s *ClusterProcessor) reconcileEIPPool() error {
+	nat := &s.alicloudCluster.Status.Network.Nat
+	specs := s.alicloudCluster.Spec.Network.Nat.EIPs
+	if len(specs) == 0 && len(nat.EIPs) == 0 {
+		return nil
+	}
+
+	adopt := map[string]bool{}
+	var allocate []infrav1.EIPSpec
+	for _, spec := range specs {
+		if len(spec.AllocationId) > 0 {
+			adopt[spec.AllocationId] = true
+		} else {
+			allocate = append(allocate, spec)
+		}
+	}
+	var pool []infrav1.EIP
+	var drifted, repaired []string
+	kept := 0
+	for _, recorded := range nat.EIPs {
+		id := recorded.AllocationId
+		if !adopt[id] && !(s.owns(id) && kept < len(allocate)) {
+			if err := s.releasePoolEIP(id); err != nil {
+				return err
+			}
+			continue
+		}
+
+		target, err := s.vpc.DescribeEIP(id)
+		if err != nil {
+			return errors.Wrap(err, "DescribeEIP")
+		}
+		if target == nil {
+			if !s.autoRepair() {
+				drifted = append(drifted, fmt.Sprintf("EIP %s no longer exists", id))
+				pool = append(pool, recorded)
+				if s.owns(id) {
+					kept++
+				}
+				delete(adopt, id)
+				continue
+			}
+			// an adopted EIP is looked up again below, an allocated one is replaced
+			repaired = append(repaired, fmt.Sprintf("Replaced missing EIP %s", id))
+			s.disown(id)
+			continue
+		}
+
+		if s.owns(id) {
+			kept++
+		}
+		delete(adopt, id)
+		if target, err = s.bindPoolEIP(target); err != nil {
+			return err
+		}
+		pool = append(pool, *target)
+	}
+
+	for _, spec := range specs {
+		id := spec.AllocationId
+		if !adopt[id] {
+			continue
+		}
+		target, err := s.vpc.DescribeEIP(id)
+		if err != nil {
+			return errors.Wrap(err, "DescribeEIP")
+		}
+		if target == nil {
+			return errors.Errorf("EIP %s not found", id)
+		}
+		if target, err = s.bindPoolEIP(target); err != nil {
+			return err
+		}
+		pool = append(pool, *target)
+	}
+
+	for _, spec := range allocate[kept:] {
+		id, err := s.vpc.CreateEIP(spec, s.alicloudCluster.Status.Network.VPC.VpcId)
+		if err != nil {
+			s.warningf("FailedCreateEIP", err, "Failed to allocate EIP")
+			return errors.Wrap(err, "CreateEIP")
+		}
+		s.eventf("SuccessfulCreateEIP", "Allocated EIP %s", id)
+		s.own(id)
+		_ = s.patch()
+
+		target, err := s.vpc.WaitEIPStatus(id, infrav1.EIPAvailable)
+		if err != nil {
+			return errors.Wrap(err, "WaitEIPStatus")
+		}
+		if target, err = s.bindPoolEIP(target); err != nil {
+			return err
+		}
+		pool = append(pool, *target)
+	}
+
+	nat.EIPs = pool
+
+	switch {
+	case len(drifted) > 0:
+		s.markDrifted(infrav1.EIPPoolReadyCondition, "%s", strings.Join(drifted, "; "))
+	case len(repaired) > 0:
+		s.markRepaired(infrav1.EIPPoolReadyCondition, "%s", strings.Join(repaired, "; "))
+	default:
+		s.markAvailable(infrav1.EIPPoolReadyCondition)
+	}
+	return nil
+}
+
+// bindPoolEIP associates an EIP with the NAT gateway unless it already is.
+func (s *ClusterProcessor) bindPoolEIP(eip *infrav1.EIP) (*infrav1.EIP, error) {
+	ngw := &s.alicloudCluster.Status.Network.Nat.NatGateway
+	if eip.Status == infrav1.EIPInUse && eip.InstanceId == ngw.NatGatewayId {
+		return eip, nil
+	}
+	if eip.Status != infrav1.EIPAvailable {
+		return nil, errors.Errorf("EIP %s is %s and associated with %s", eip.AllocationId, eip.Status, eip.InstanceId)
+	}
+
+	if err := s.vpc.AssociateEipToNatGateway(eip, ngw); err != nil {
+		s.warningf("FailedAssociateEIP", err, "Failed to associate EIP %s with NAT gateway %s", eip.AllocationId, ngw.NatGatewayId)
+		return nil, errors.Wrap(err, "AssociateEipToNatGateway")
+	}
+	s.eventf("SuccessfulAssociateEIP", "Associated EIP %s with NAT gateway %s", eip.AllocationId, ngw.NatGatewayId)
+	target, err := s.vpc.WaitEIPStatus(eip.AllocationId, infrav1.EIPInUse)
+	return target, errors.Wrap(err, "WaitEIPStatus")
+}
+
+// releasePoolEIP takes an EIP out of the bandwidth package and the NAT gateway,
+// then releases it if the provider allocated it.
+func (s *ClusterProcessor) releasePoolEIP(id string) error {
+	nat := &s.alicloudCluster.Status.Network.Nat
+
+	target, err := s.vpc.DescribeEIP(id)
+	if err != nil {
+		return errors.Wrap(err, "DescribeEIP")
+	}
+	if target != nil {
+		pkg := &nat.CommonBandwidthPackage
+		if contains(pkg.AllocationIds, id) {
+			if err := s.vpc.RemoveCommonBandwidthPackageIp(pkg.BandwidthPackageId, id); err != nil {
+				s.warningf("FailedRemoveBandwidthPackageIp", err, "Failed to remove EIP %s from bandwidth package %s", id, pkg.BandwidthPackageId)
+				return errors.Wrap(err, "RemoveCommonBandwidthPackageIp")
+			}
+			s.eventf("SuccessfulRemoveBandwidthPackageIp", "Removed EIP %s from bandwidth package %s", id, pkg.BandwidthPackageId)
+			pkg.AllocationIds = filter(pkg.AllocationIds, id)
+		}
+
+		if target.Status == infrav1.EIPInUse && target.InstanceId == nat.NatGateway.NatGatewayId {
+			if err := s.vpc.UnassociateEipToNatGateway(target, &nat.NatGateway); err != nil {
+				s.warningf("FailedUnassociateEIP", err, "Failed to unassociate EIP %s from NAT gateway %s", id, nat.NatGateway.NatGatewayId)
+				return errors.Wrap(err, "UnassociateEipToNatGateway")
+			}
+			s.eventf("SuccessfulUnassociateEIP", "Unassociated EIP %s from NAT gateway %s", id, nat.NatGateway.NatGatewayId)
+			if _, err := s.vpc.WaitEIPStatus(id, infrav1.EIPAvailable); err != nil {
+				return errors.Wrap(err, "WaitEIPStatus Available")
+			}
+		}
+
+		if s.owns(id) {
+			if err := s.vpc.DeleteEIP(id); err != nil {
+				s.warningf("FailedDeleteEIP", err, "Failed to delete EIP %s", id)
+				return errors.Wrap(err, "DeleteEIP")
+			}
+			s.eventf("SuccessfulDeleteEIP", "Deleted EIP %s", id)
+		}
+	}
+
+	s.disown(id)
+	for i := range nat.EIPs {
+		if nat.EIPs[i].AllocationId == id {
+			nat.EIPs = append(nat.EIPs[:i], nat.EIPs[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// deleteEIPPool releases the whole EIP pool.
+func (s *ClusterProcessor) deleteEIPPool() error {
+	nat := &s.alicloudCluster.Status.Network.Nat
+	for len(nat.EIPs) > 0 {
+		if err := s.releasePoolEIP(nat.EIPs[0].AllocationId); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reconcileBandwidthPackage creates or adopts the common bandwidth package and adds the EIP and EIP pool to it.
+// Removing it from the spec takes the EIPs out again and releases the package if the provider created it.
+func (s *ClusterProcessor) reconcileBandwidthPackage() error {
+	spec := s.alicloudCluster.Spec.Network.Nat.CommonBandwidthPackage
+	status := &s.alicloudCluster.Status.Network.Nat.CommonBandwidthPackage
+
+	if spec == nil || (len(spec.BandwidthPackageId) > 0 && len(status.BandwidthPackageId) > 0 && spec.BandwidthPackageId != status.BandwidthPackageId) {
+		if len(status.BandwidthPackageId) == 0 {
+			return nil
+		}
+		if err := s.deleteBandwidthPackage(); err != nil {
+			return errors.Wrap(err, "deleteBandwidthPackage")
+		}
+		if spec == nil {
+			s.alicloudCluster.Status.Conditions.Remove(infrav1.BandwidthPackageReadyCondition)
+			return nil
+		}
+	}
+
+	var repaired string
+	if len(status.BandwidthPackageId) > 0 {
+		target, err := s.vpc.DescribeCommonBandwidthPackage(status.BandwidthPackageId)
+		if err != nil {
+			return errors.Wrap(err, "DescribeCommonBandwidthPackage")
+		}
+		if target == nil {
+			if !s.autoRepair() {
+				s.markDrifted(infrav1.BandwidthPackageReadyCondition, "Common bandwidth package %s no longer exists", status.BandwidthPackageId)
+				return nil
+			}
+			repaired = fmt.Sprintf("Replaced missing common bandwidth package %s", status.BandwidthPackageId)
+			s.disown(status.BandwidthPackageId)
+			*status = infrav1.CommonBandwidthPackage{}
+		}
+	}
+
+	if len(status.BandwidthPackageId) == 0 {
+		if len(spec.BandwidthPackageId) > 0 {
+			status.BandwidthPackageId = spec.BandwidthPackageId
+		} else {
+			id, err := s.vpc.CreateCommonBandwidthPackage(*spec)
+			if err != nil {
+				s.warningf("FailedCreateBandwidthPackage", err, "Failed to create common bandwidth package")
+				return errors.Wrap(err, "CreateCommonBandwidthPackage")
+			}
+			s.eventf("SuccessfulCreateBandwidthPackage", "Created common bandwidth package %s", id)
+			s.own(id)
+			status.BandwidthPackageId = id
+			_ = s.patch()
+		}
+	}
+
+	target, err := s.vpc.WaitCommonBandwidthPackageReady(status.BandwidthPackageId)
+	if err != nil {
+		return errors.Wrap(err, "WaitCommonBandwidthPackageReady")
+	}
+	for _, id := range s.natEIPIDs() {
+		if contains(target.AllocationIds, id) {
+			continue
+		}
+		if err := s.vpc.AddCommonBandwidthPackageIp(target.BandwidthPackageId, id); err != nil {
+			s.warningf("FailedAddBandwidthPackageIp", err, "Failed to add EIP %s to bandwidth package %s", id, target.BandwidthPackageId)
+			return errors.Wrap(err, "AddCommonBandwidthPackageIp")
+		}
+		s.eventf("SuccessfulAddBandwidthPackageIp", "Added EIP %s to bandwidth package %s", id, target.BandwidthPackageId)
+		target.AllocationIds = append(target.AllocationIds, id)
+	}
+
+	target.DeepCopyInto(status)
+	if len(repaired) > 0 {
+		s.markRepaired(infrav1.BandwidthPackageReadyCondition, "%s", repaired)
+	} else {
+		s.markAvailable(infrav1.BandwidthPackageReadyCondition)
+	}
+	return nil
+}
+
+// deleteBandwidthPackage takes the cluster EIPs out of the common bandwidth package
+// and releases the package if the provider created it.
+func (s *ClusterProcessor) deleteBandwidthPackage() error {
+	status := &s.alicloudCluster.Status.Network.Nat.CommonBandwidthPackage
+	if len(status.BandwidthPackageId) == 0 {
+		return nil
+	}
+
+	target, err := s.vpc.DescribeCommonBandwidthPackage(status.BandwidthPackageId)
+	if err != nil {
+		return errors.Wrap(err, "DescribeCommonBandwidthPackage")
+	}
+	if target != nil {
+		for _, id := range s.natEIPIDs() {
+			if !contains(target.AllocationIds, id) {
+				continue
+			}
+			if err := s.vpc.RemoveCommonBandwidthPackageIp(target.BandwidthPackageId, id); err != nil {
+				s.warningf("FailedRemoveBandwidthPackageIp", err, "Failed to remove EIP %s from bandwidth package %s", id, target.BandwidthPackageId)
+				return errors.Wrap(err, "RemoveCommonBandwidthPackageIp")
+			}
+			s.eventf("SuccessfulRemoveBandwidthPackageIp", "Removed EIP %s from bandwidth package %s", id, target.BandwidthPackageId)
+		}
+
+		if s.owns(target.BandwidthPackageId) {
+			if err := s.vpc.DeleteCommonBandwidthPackage(target.BandwidthPackageId); err != nil {
+				s.warningf("FailedDeleteBandwidthPackage", err, "Failed to delete common bandwidth package %s", target.BandwidthPackageId)
+				return errors.Wrap(err, "DeleteCommonBandwidthPackage")
+			}
+			s.eventf("SuccessfulDeleteBandwidthPackage", "Deleted common bandwidth package %s", target.BandwidthPackageId)
+		}
+	}
+
+	s.disown(status.BandwidthPackageId)
+	*status = infrav1.CommonBandwidthPackage{}
+	return nil
+}
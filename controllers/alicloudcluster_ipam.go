@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-alicloud/api/v1alpha2"
+	"sigs.k8s.io/cluster-api-provider-alicloud/pkg/ipam"
+)
+
+const defaultVSwitchCidrMask = 24
+
+// vswitchCIDR returns the CIDR block of the VSwitch about to be created in the cluster zone.
+// A CidrBlock from the spec is checked against the VPC, its other VSwitches and the cluster pod and
+// service ranges; an empty one is allocated from the VPC CIDR block and recorded in the status.
+func (s *ClusterProcessor) vswitchCIDR(spec infrav1.VSwitchSpec) (string, error) {
+	network := &s.alicloudCluster.Status.Network
+	zoneID := s.alicloudCluster.Spec.ZoneId
+	for _, allocation := range network.CIDRAllocations {
+		if allocation.ZoneId == zoneID {
+			return allocation.CidrBlock, nil
+		}
+	}
+
+	vswitches, err := s.vswitch.DescribeByVpc(network.VPC.VpcId)
+	if err != nil {
+		return "", errors.Wrap(err, "DescribeByVpc")
+	}
+	var used []string
+	for _, vsw := range vswitches {
+		used = append(used, vsw.CidrBlock)
+	}
+	reserved := s.clusterCIDRs()
+
+	if len(spec.CidrBlock) > 0 {
+		if len(network.VPC.CidrBlock) > 0 {
+			ok, err := ipam.Contains(network.VPC.CidrBlock, spec.CidrBlock)
+			if err != nil {
+				return "", err
+			}
+			if !ok {
+				return "", errors.Errorf("VSwitch CIDR block %s is outside VPC CIDR block %s", spec.CidrBlock, network.VPC.CidrBlock)
+			}
+		}
+		for _, cidr := range append(used, reserved...) {
+			overlap, err := ipam.Overlaps(spec.CidrBlock, cidr)
+			if err != nil {
+				return "", err
+			}
+			if overlap {
+				return "", errors.Errorf("VSwitch CIDR block %s overlaps %s", spec.CidrBlock, cidr)
+			}
+		}
+		return spec.CidrBlock, nil
+	}
+
+	if len(network.VPC.CidrBlock) == 0 {
+		return "", errors.Errorf("VPC %s has no CIDR block to allocate from", network.VPC.VpcId)
+	}
+	mask := spec.CidrMask
+	if mask == 0 {
+		mask = defaultVSwitchCidrMask
+	}
+	if mask < 16 || mask > 29 {
+		return "", errors.Errorf("VSwitch CIDR mask %d is out of range 16-29", mask)
+	}
+	cidr, err := ipam.Allocate(network.VPC.CidrBlock, mask, append(used, reserved...))
+	if err != nil {
+		return "", err
+	}
+
+	s.Info("allocated VSwitch CIDR block", "zone", zoneID, "cidr", cidr)
+	network.CIDRAllocations = append(network.CIDRAllocations, infrav1.CIDRAllocation{ZoneId: zoneID, CidrBlock: cidr})
+	if err := s.patch(); err != nil {
+		return "", errors.Wrap(err, "record CIDR allocation")
+	}
+	return cidr, nil
+}
+
+// clusterCIDRs returns the pod and service CIDR blocks of the CAPI cluster.
+func (s *ClusterProcessor) clusterCIDRs() []string {
+	clusterNetwork := s.cluster.Spec.ClusterNetwork
+	if clusterNetwork == nil {
+		return nil
+	}
+
+	var cidrs []string
+	if clusterNetwork.Pods != nil {
+		cidrs = append(cidrs, clusterNetwork.Pods.CIDRBlocks...)
+	}
+	if clusterNetwork.Services != nil {
+		cidrs = append(cidrs, clusterNetwork.Services.CIDRBlocks...)
+	}
+	return cidrs
+}
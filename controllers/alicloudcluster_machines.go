@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-alicloud/api/v1alpha2"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha2"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const machineDeletionPollInterval = 10 * time.Second
+
+// machinesDeleted reports whether no AlicloudMachine of the cluster is left, recording the outcome in
+// MachinesDeletedCondition.
+func (s *ClusterProcessor) machinesDeleted() (bool, error) {
+	names, err := s.clusterMachines()
+	if err != nil {
+		return false, err
+	}
+
+	conds := &s.alicloudCluster.Status.Conditions
+	if len(names) == 0 {
+		conds.MarkTrue(infrav1.MachinesDeletedCondition, infrav1.MachinesDeletedReason, "")
+		return true, nil
+	}
+
+	msg := fmt.Sprintf("waiting for %d AlicloudMachines to be deleted: %s", len(names), strings.Join(names, ", "))
+	if c := conds.Get(infrav1.MachinesDeletedCondition); c == nil || c.Message != msg {
+		s.eventf(infrav1.WaitingForMachinesReason, "%s", msg)
+	}
+	s.Info("waiting for machines", "machines", names)
+	conds.MarkFalse(infrav1.MachinesDeletedCondition, infrav1.WaitingForMachinesReason, msg)
+	s.alicloudCluster.Status.Message = msg
+	return false, nil
+}
+
+// clusterMachines returns the names of the AlicloudMachines in the cluster namespace that belong to
+// the cluster, either by their own cluster label, by that of their owner Machine or by an owner reference
+// to the cluster. Machines that cannot be attributed to any cluster are counted while they still hold an
+// ECS instance, since it would keep the VPC and VSwitch from being deleted.
+func (s *ClusterProcessor) clusterMachines() ([]string, error) {
+	ctx := context.TODO()
+	list := &infrav1.AlicloudMachineList{}
+	if err := s.client.List(ctx, list, client.InNamespace(s.alicloudCluster.Namespace)); err != nil {
+		return nil, errors.Wrap(err, "List AlicloudMachines")
+	}
+
+	var names []string
+	for i := range list.Items {
+		item := &list.Items[i]
+		clusterName := item.Labels[clusterv1.MachineClusterLabelName]
+		if len(clusterName) == 0 {
+			machine, err := util.GetOwnerMachine(ctx, s.client, item.ObjectMeta)
+			if err != nil && !apierrors.IsNotFound(err) {
+				return nil, errors.Wrapf(err, "GetOwnerMachine %s", item.Name)
+			}
+			if machine != nil {
+				clusterName = machine.Labels[clusterv1.MachineClusterLabelName]
+			}
+		}
+		if len(clusterName) == 0 && (s.ownedByCluster(item.OwnerReferences) || len(item.Status.ID) > 0) {
+			clusterName = s.cluster.Name
+		}
+		if clusterName == s.cluster.Name {
+			names = append(names, item.Name)
+		}
+	}
+	return names, nil
+}
+
+func (s *ClusterProcessor) ownedByCluster(refs []metav1.OwnerReference) bool {
+	for _, ref := range refs {
+		if ref.Kind == "Cluster" && ref.Name == s.cluster.Name && strings.HasPrefix(ref.APIVersion, clusterv1.GroupVersion.Group+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,421 @@
+package controllers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-alicloud/api/v1alpha2"
+)
+
+// reconcileNatEntries brings the SNAT and DNAT tables of the NAT gateway in line with Spec.Network.Nat.
+func (s *ClusterProcessor) reconcileNatEntries() error {
+	if err := s.reconcileSnatEntries(); err != nil {
+		return errors.Wrap(err, "reconcileSnatEntries")
+	}
+	return errors.Wrap(s.reconcileForwardEntries(), "reconcileForwardEntries")
+}
+
+// natEIPAddress returns the address of an EIP used by a NAT entry, associating it with the NAT gateway
+// first if needed. An empty allocationID means the EIP of the NAT gateway itself.
+func (s *ClusterProcessor) natEIPAddress(allocationID string) (string, error) {
+	nat := &s.alicloudCluster.Status.Network.Nat
+	if len(allocationID) == 0 || allocationID == nat.EIP.AllocationId {
+		return nat.EIP.IpAddress, nil
+	}
+	for _, eip := range nat.EIPs {
+		if eip.AllocationId == allocationID {
+			return eip.IpAddress, nil
+		}
+	}
+
+	eip, err := s.vpc.DescribeEIP(allocationID)
+	if err != nil {
+		return "", errors.Wrap(err, "DescribeEIP")
+	}
+	if eip == nil {
+		return "", errors.Errorf("EIP %s not found", allocationID)
+	}
+	if eip.Status == infrav1.EIPInUse && eip.InstanceId == nat.NatGateway.NatGatewayId {
+		return eip.IpAddress, nil
+	}
+	if eip.Status != infrav1.EIPAvailable {
+		return "", errors.Errorf("EIP %s is %s and associated with %s", allocationID, eip.Status, eip.InstanceId)
+	}
+
+	if err := s.vpc.AssociateEipToNatGateway(eip, &nat.NatGateway); err != nil {
+		s.warningf("FailedAssociateEIP", err, "Failed to associate EIP %s with NAT gateway %s", allocationID, nat.NatGateway.NatGatewayId)
+		return "", errors.Wrap(err, "AssociateEipToNatGateway")
+	}
+	s.eventf("SuccessfulAssociateEIP", "Associated EIP %s with NAT gateway %s", allocationID, nat.NatGateway.NatGatewayId)
+	nat.AssociatedEIPs = append(nat.AssociatedEIPs, allocationID)
+	if eip, err = s.vpc.WaitEIPStatus(allocationID, infrav1.EIPInUse); err != nil {
+		return "", errors.Wrap(err, "WaitEIPStatus")
+	}
+	return eip.IpAddress, nil
+}
+
+// desiredSnatEntries returns the SNAT entries of the spec and their names. Without any, the cluster
+// VSwitch gets an entry using the EIP of the NAT gateway, as it always has. Entries without
+// AllocationIds use the EIP together with the EIP pool.
+func (s *ClusterProcessor) desiredSnatEntries() ([]infrav1.SnatEntry, []string, error) {
+	specs := s.alicloudCluster.Spec.Network.Nat.SnatEntries
+	if len(specs) == 0 {
+		specs = []infrav1.SnatEntrySpec{{}}
+	}
+
+	var entries []infrav1.SnatEntry
+	var names []string
+	for _, spec := range specs {
+		entry := infrav1.SnatEntry{SourceVSwitchId: spec.SourceVSwitchId, SourceCIDR: spec.SourceCIDR}
+		if len(entry.SourceCIDR) == 0 && len(entry.SourceVSwitchId) == 0 {
+			entry.SourceVSwitchId = s.alicloudCluster.Status.Network.VSwitch.VSwitchId
+		}
+
+		ids := spec.AllocationIds
+		if len(ids) == 0 {
+			ids = s.natEIPIDs()
+		}
+		var ips []string
+		for _, id := range ids {
+			ip, err := s.natEIPAddress(id)
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "SNAT entry for %s", entry.Source())
+			}
+			ips = append(ips, ip)
+		}
+		entry.SnatIp = strings.Join(ips, ",")
+
+		name := spec.Name
+		if len(name) == 0 {
+			name = s.alicloudCluster.Status.Network.Nat.NatGateway.Name
+		}
+		entries = append(entries, entry)
+		names = append(names, name)
+	}
+	return entries, names, nil
+}
+
+// reconcileSnatEntries creates the wanted SNAT entries that are missing and deletes the ones the provider
+// created earlier but are no longer wanted. Entries the provider does not own are left alone.
+func (s *ClusterProcessor) reconcileSnatEntries() error {
+	nat := &s.alicloudCluster.Status.Network.Nat
+	if len(nat.NatGateway.SnatTableIds.SnatTableId) == 0 {
+		return nil
+	}
+	tableID := nat.NatGateway.SnatTableIds.SnatTableId[0]
+
+	desired, names, err := s.desiredSnatEntries()
+	if err != nil {
+		return err
+	}
+	list, err := s.vpc.DescribeSnatEntries(tableID)
+	if err != nil {
+		return errors.Wrap(err, "DescribeSnatEntries")
+	}
+
+	actual := make(map[string]infrav1.SnatEntry, len(list))
+	for i := range list {
+		e := infrav1.SnatEntryFromTable(&list[i])
+		actual[e.Source()] = e
+	}
+	recorded := map[string]infrav1.SnatEntry{}
+	for _, e := range nat.SnatEntries {
+		recorded[e.Source()] = e
+	}
+
+	var result []infrav1.SnatEntry
+	var drifted, repaired []string
+	wanted := map[string]bool{}
+	for i, want := range desired {
+		wanted[want.Source()] = true
+
+		if cur, ok := actual[want.Source()]; ok {
+			if sameIPs(cur.SnatIp, want.SnatIp) {
+				result = append(result, cur)
+				continue
+			}
+			if !s.owns(cur.SnatEntryId) {
+				return errors.Errorf("SNAT entry %s for %s uses %s and was not created by the provider", cur.SnatEntryId, cur.Source(), cur.SnatIp)
+			}
+			if err := s.vpc.DeleteSnatEntry(tableID, cur.SnatEntryId); err != nil {
+				s.warningf("FailedDeleteSnatEntry", err, "Failed to delete SNAT entry %s", cur.SnatEntryId)
+				return errors.Wrap(err, "DeleteSnatEntry")
+			}
+			s.eventf("SuccessfulDeleteSnatEntry", "Deleted SNAT entry %s for %s to change its IPs to %s", cur.SnatEntryId, cur.Source(), want.SnatIp)
+			s.disown(cur.SnatEntryId)
+		} else if old, ok := recorded[want.Source()]; ok {
+			if !s.autoRepair() {
+				drifted = append(drifted, fmt.Sprintf("SNAT entry %s for %s no longer exists", old.SnatEntryId, old.Source()))
+				result = append(result, old)
+				continue
+			}
+			repaired = append(repaired, fmt.Sprintf("Replaced missing SNAT entry %s for %s", old.SnatEntryId, old.Source()))
+			s.disown(old.SnatEntryId)
+		}
+
+		id, err := s.vpc.CreateSnatEntry(tableID, want, names[i])
+		if err != nil {
+			s.warningf("FailedCreateSnatEntry", err, "Failed to create SNAT entry for %s", want.Source())
+			return errors.Wrap(err, "CreateSnatEntry")
+		}
+		s.eventf("SuccessfulCreateSnatEntry", "Created SNAT entry %s for %s", id, want.Source())
+		s.own(id)
+		want.SnatEntryId = id
+		result = append(result, want)
+	}
+
+	for _, cur := range actual {
+		if wanted[cur.Source()] || !s.owns(cur.SnatEntryId) {
+			continue
+		}
+		if err := s.vpc.DeleteSnatEntry(tableID, cur.SnatEntryId); err != nil {
+			s.warningf("FailedDeleteSnatEntry", err, "Failed to delete SNAT entry %s", cur.SnatEntryId)
+			return errors.Wrap(err, "DeleteSnatEntry")
+		}
+		s.eventf("SuccessfulDeleteSnatEntry", "Deleted SNAT entry %s for %s", cur.SnatEntryId, cur.Source())
+		s.disown(cur.SnatEntryId)
+	}
+
+	nat.SnatEntries = result
+	nat.SnatEntryId = ""
+
+	switch {
+	case len(drifted) > 0:
+		s.markDrifted(infrav1.SnatEntryReadyCondition, "%s", strings.Join(drifted, "; "))
+	case len(repaired) > 0:
+		s.markRepaired(infrav1.SnatEntryReadyCondition, "%s", strings.Join(repaired, "; "))
+	default:
+		s.markAvailable(infrav1.SnatEntryReadyCondition)
+	}
+	return nil
+}
+
+func (s *ClusterProcessor) desiredForwardEntries() ([]infrav1.ForwardEntry, []string, error) {
+	var entries []infrav1.ForwardEntry
+	var names []string
+	for _, spec := range s.alicloudCluster.Spec.Network.Nat.ForwardEntries {
+		ip, err := s.natEIPAddress(spec.AllocationId)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "DNAT entry for %s:%s", spec.InternalIp, spec.InternalPort)
+		}
+		entry := infrav1.ForwardEntry{
+			ExternalIp:   ip,
+			ExternalPort: spec.ExternalPort,
+			InternalIp:   spec.InternalIp,
+			InternalPort: spec.InternalPort,
+			IpProtocol:   spec.IpProtocol,
+		}
+		if len(entry.InternalPort) == 0 {
+			entry.InternalPort = entry.ExternalPort
+		}
+		if len(entry.IpProtocol) == 0 {
+			entry.IpProtocol = "Any"
+		}
+		entries = append(entries, entry)
+		names = append(names, spec.Name)
+	}
+	return entries, names, nil
+}
+
+// forwardTableID returns the DNAT table of the NAT gateway. Clusters created before DNAT support
+// did not record it, so it is looked up once.
+func (s *ClusterProcessor) forwardTableID() (string, error) {
+	ngw := &s.alicloudCluster.Status.Network.Nat.NatGateway
+	if len(ngw.ForwardTableIds.ForwardTableId) == 0 {
+		target, err := s.vpc.DescribeNatGateway(ngw.NatGatewayId)
+		if err != nil {
+			return "", errors.Wrap(err, "DescribeNatGateway")
+		}
+		if target == nil {
+			return "", errors.Errorf("NAT gateway %s not found", ngw.NatGatewayId)
+		}
+		ngw.ForwardTableIds = target.ForwardTableIds
+	}
+	if len(ngw.ForwardTableIds.ForwardTableId) == 0 {
+		return "", errors.Errorf("NAT gateway %s has no forward table", ngw.NatGatewayId)
+	}
+	return ngw.ForwardTableIds.ForwardTableId[0], nil
+}
+
+// reconcileForwardEntries keeps the DNAT table in line with the spec the same way reconcileSnatEntries does.
+func (s *ClusterProcessor) reconcileForwardEntries() error {
+	nat := &s.alicloudCluster.Status.Network.Nat
+	if len(s.alicloudCluster.Spec.Network.Nat.ForwardEntries) == 0 && len(nat.ForwardEntries) == 0 {
+		return nil
+	}
+
+	tableID, err := s.forwardTableID()
+	if err != nil {
+		return err
+	}
+	desired, names, err := s.desiredForwardEntries()
+	if err != nil {
+		return err
+	}
+	list, err := s.vpc.DescribeForwardEntries(tableID)
+	if err != nil {
+		return errors.Wrap(err, "DescribeForwardEntries")
+	}
+
+	actual := make(map[string]infrav1.ForwardEntry, len(list))
+	for i := range list {
+		e := infrav1.ForwardEntryFromTable(&list[i])
+		actual[e.Key()] = e
+	}
+	recorded := map[string]infrav1.ForwardEntry{}
+	for _, e := range nat.ForwardEntries {
+		recorded[e.Key()] = e
+	}
+
+	var result []infrav1.ForwardEntry
+	var drifted, repaired []string
+	wanted := map[string]bool{}
+	for i, want := range desired {
+		wanted[want.Key()] = true
+
+		if cur, ok := actual[want.Key()]; ok {
+			if cur.InternalIp == want.InternalIp && cur.InternalPort == want.InternalPort {
+				result = append(result, cur)
+				continue
+			}
+			if !s.owns(cur.ForwardEntryId) {
+				return errors.Errorf("DNAT entry %s for %s forwards to %s:%s and was not created by the provider",
+					cur.ForwardEntryId, cur.Key(), cur.InternalIp, cur.InternalPort)
+			}
+			if err := s.vpc.DeleteForwardEntry(tableID, cur.ForwardEntryId); err != nil {
+				s.warningf("FailedDeleteForwardEntry", err, "Failed to delete DNAT entry %s", cur.ForwardEntryId)
+				return errors.Wrap(err, "DeleteForwardEntry")
+			}
+			s.eventf("SuccessfulDeleteForwardEntry", "Deleted DNAT entry %s for %s to change its target", cur.ForwardEntryId, cur.Key())
+			s.disown(cur.ForwardEntryId)
+		} else if old, ok := recorded[want.Key()]; ok {
+			if !s.autoRepair() {
+				drifted = append(drifted, fmt.Sprintf("DNAT entry %s for %s no longer exists", old.ForwardEntryId, old.Key()))
+				result = append(result, old)
+				continue
+			}
+			repaired = append(repaired, fmt.Sprintf("Replaced missing DNAT entry %s for %s", old.ForwardEntryId, old.Key()))
+			s.disown(old.ForwardEntryId)
+		}
+
+		id, err := s.vpc.CreateForwardEntry(tableID, want, names[i])
+		if err != nil {
+			s.warningf("FailedCreateForwardEntry", err, "Failed to create DNAT entry for %s", want.Key())
+			return errors.Wrap(err, "CreateForwardEntry")
+		}
+		s.eventf("SuccessfulCreateForwardEntry", "Created DNAT entry %s for %s", id, want.Key())
+		s.own(id)
+		want.ForwardEntryId = id
+		result = append(result, want)
+	}
+
+	for _, cur := range actual {
+		if wanted[cur.Key()] || !s.owns(cur.ForwardEntryId) {
+			continue
+		}
+		if err := s.vpc.DeleteForwardEntry(tableID, cur.ForwardEntryId); err != nil {
+			s.warningf("FailedDeleteForwardEntry", err, "Failed to delete DNAT entry %s", cur.ForwardEntryId)
+			return errors.Wrap(err, "DeleteForwardEntry")
+		}
+		s.eventf("SuccessfulDeleteForwardEntry", "Deleted DNAT entry %s for %s", cur.ForwardEntryId, cur.Key())
+		s.disown(cur.ForwardEntryId)
+	}
+
+	nat.ForwardEntries = result
+
+	switch {
+	case len(drifted) > 0:
+		s.markDrifted(infrav1.ForwardEntryReadyCondition, "%s", strings.Join(drifted, "; "))
+	case len(repaired) > 0:
+		s.markRepaired(infrav1.ForwardEntryReadyCondition, "%s", strings.Join(repaired, "; "))
+	default:
+		s.markAvailable(infrav1.ForwardEntryReadyCondition)
+	}
+	return nil
+}
+
+// deleteNatEntries deletes the SNAT and DNAT entries owned by the provider and unassociates
+// the EIPs it bound to the NAT gateway for them, so the gateway and its own EIP can be released.
+func (s *ClusterProcessor) deleteNatEntries() error {
+	nat := &s.alicloudCluster.Status.Network.Nat
+
+	if len(nat.ForwardEntries) > 0 {
+		tableID, err := s.forwardTableID()
+		if err != nil {
+			return err
+		}
+		for _, e := range nat.ForwardEntries {
+			if !s.owns(e.ForwardEntryId) {
+				continue
+			}
+			if err := s.vpc.DeleteForwardEntry(tableID, e.ForwardEntryId); err != nil {
+				s.warningf("FailedDeleteForwardEntry", err, "Failed to delete DNAT entry %s", e.ForwardEntryId)
+				return errors.Wrap(err, "DeleteForwardEntry")
+			}
+			s.eventf("SuccessfulDeleteForwardEntry", "Deleted DNAT entry %s", e.ForwardEntryId)
+			s.disown(e.ForwardEntryId)
+		}
+		nat.ForwardEntries = nil
+	}
+
+	if tables := nat.NatGateway.SnatTableIds.SnatTableId; len(tables) > 0 {
+		ids := []string{}
+		if len(nat.SnatEntryId) > 0 {
+			ids = append(ids, nat.SnatEntryId)
+		}
+		for _, e := range nat.SnatEntries {
+			ids = append(ids, e.SnatEntryId)
+		}
+		for _, id := range ids {
+			if !s.owns(id) {
+				continue
+			}
+			if err := s.vpc.DeleteSnatEntry(tables[0], id); err != nil {
+				s.warningf("FailedDeleteSnatEntry", err, "Failed to delete SNAT entry %s", id)
+				return errors.Wrap(err, "DeleteSnatEntry")
+			}
+			s.eventf("SuccessfulDeleteSnatEntry", "Deleted SNAT entry %s", id)
+			s.disown(id)
+		}
+		nat.SnatEntryId = ""
+		nat.SnatEntries = nil
+	}
+
+	for len(nat.AssociatedEIPs) > 0 {
+		id := nat.AssociatedEIPs[0]
+		eip, err := s.vpc.DescribeEIP(id)
+		if err != nil {
+			return errors.Wrap(err, "DescribeEIP")
+		}
+		if eip != nil && eip.Status == infrav1.EIPInUse && eip.InstanceId == nat.NatGateway.NatGatewayId {
+			if err := s.vpc.UnassociateEipToNatGateway(eip, &nat.NatGateway); err != nil {
+				s.warningf("FailedUnassociateEIP", err, "Failed to unassociate EIP %s from NAT gateway %s", id, nat.NatGateway.NatGatewayId)
+				return errors.Wrap(err, "UnassociateEipToNatGateway")
+			}
+			s.eventf("SuccessfulUnassociateEIP", "Unassociated EIP %s from NAT gateway %s", id, nat.NatGateway.NatGatewayId)
+			if _, err := s.vpc.WaitEIPStatus(id, infrav1.EIPAvailable); err != nil {
+				return errors.Wrap(err, "WaitEIPStatus Available")
+			}
+		}
+		nat.AssociatedEIPs = nat.AssociatedEIPs[1:]
+	}
+	return nil
+}
+
+// sameIPs compares two comma separated address lists regardless of order.
+func sameIPs(a, b string) bool {
+	x, y := strings.Split(a, ","), strings.Split(b, ",")
+	if len(x) != len(y) {
+		return false
+	}
+	sort.Strings(x)
+	sort.Strings(y)
+	for i := range x {
+		if strings.TrimSpace(x[i]) != strings.TrimSpace(y[i]) {
+			return false
+		}
+	}
+	return true
+}
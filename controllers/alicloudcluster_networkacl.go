@@ -0,0 +1,222 @@
+package controllers
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-alicloud/api/v1alpha2"
+)
+
+const networkAclEntryCustom = "custom"
+
+// reconcileNetworkAcl creates or adopts the network ACL of Spec.Network.NetworkAcl, keeps its entries in
+// sync with the spec and associates it with the cluster VSwitch. The entries of an adopted ACL are left
+// alone unless the spec lists some. Entries or associations changed out-of-band are drift and only restored
+// with Spec.Network.AutoRepair. Removing Spec.Network.NetworkAcl only takes effect when the cluster is deleted.
+func (s *ClusterProcessor) reconcileNetworkAcl() error {
+	spec := s.alicloudCluster.Spec.Network.NetworkAcl
+	if spec == nil {
+		return nil
+	}
+
+	s.Info("reconcileNetworkAcl")
+
+	status := &s.alicloudCluster.Status.Network.NetworkAcl
+	id := status.NetworkAclId
+	if len(id) == 0 {
+		id = spec.NetworkAclId
+	}
+
+	var repaired []string
+	if len(id) > 0 {
+		target, err := s.networkAcl.Describe(id)
+		if err != nil {
+			return errors.Wrapf(err, "Describe %v", id)
+		}
+		if target == nil {
+			if len(status.NetworkAclId) == 0 {
+				return errors.Errorf("target not found: %v", id)
+			}
+			if !s.owns(id) || !s.autoRepair() {
+				s.markDrifted(infrav1.NetworkAclReadyCondition, "Network ACL %s no longer exists", id)
+				return nil
+			}
+			repaired = append(repaired, "Replaced missing network ACL "+id)
+			s.disown(id)
+			*status = infrav1.NetworkAcl{}
+			id = ""
+		}
+	}
+	if len(id) == 0 {
+		var err error
+		id, err = s.networkAcl.Create(*spec, s.alicloudCluster.Status.Network.VPC.VpcId)
+		if err != nil {
+			s.warningf("FailedCreateNetworkAcl", err, "Failed to create network ACL")
+			return errors.Wrap(err, "Create")
+		}
+		s.eventf("SuccessfulCreateNetworkAcl", "Created network ACL %s", id)
+		s.own(id)
+	}
+
+	target, err := s.networkAcl.WaitReady(id)
+	if err != nil {
+		return errors.Wrapf(err, "WaitReady %v", id)
+	}
+	status.FillFrom(target)
+	_ = s.patch()
+
+	if s.owns(id) || len(spec.IngressEntries) > 0 || len(spec.EgressEntries) > 0 {
+		if !networkAclEntriesMatch(target, spec) {
+			// entries already applied for this very spec were edited out-of-band
+			drifted := networkAclEntriesApplied(status, spec)
+			if drifted && !s.autoRepair() {
+				s.markDrifted(infrav1.NetworkAclReadyCondition, "Entries of network ACL %s no longer match the spec", id)
+				return nil
+			}
+			if err := s.networkAcl.UpdateEntries(id, spec.IngressEntries, spec.EgressEntries); err != nil {
+				s.warningf("FailedUpdateNetworkAcl", err, "Failed to update entries of network ACL %s", id)
+				return errors.Wrap(err, "UpdateEntries")
+			}
+			if drifted {
+				repaired = append(repaired, "Restored entries of network ACL "+id)
+			} else {
+				s.eventf("SuccessfulUpdateNetworkAcl", "Updated entries of network ACL %s", id)
+			}
+			if target, err = s.networkAcl.WaitReady(id); err != nil {
+				return errors.Wrapf(err, "WaitReady %v", id)
+			}
+		}
+		if !networkAclEntriesApplied(status, spec) {
+			status.EntriesApplied = true
+			status.IngressEntries = normalizeNetworkAclEntries(spec.IngressEntries)
+			status.EgressEntries = normalizeNetworkAclEntries(spec.EgressEntries)
+		}
+	}
+
+	vswID := s.alicloudCluster.Status.Network.VSwitch.VSwitchId
+	if !networkAclBound(target, vswID) {
+		recorded := contains(status.VSwitchIds, vswID)
+		if recorded && !s.autoRepair() {
+			s.markDrifted(infrav1.NetworkAclReadyCondition, "VSwitch %s is no longer associated with network ACL %s", vswID, id)
+			return nil
+		}
+		if err := s.networkAcl.Associate(id, vswID); err != nil {
+			s.warningf("FailedAssociateNetworkAcl", err, "Failed to associate VSwitch %s with network ACL %s", vswID, id)
+			return errors.Wrap(err, "Associate")
+		}
+		if recorded {
+			repaired = append(repaired, "Associated VSwitch "+vswID+" with network ACL "+id+" again")
+		} else {
+			s.eventf("SuccessfulAssociateNetworkAcl", "Associated VSwitch %s with network ACL %s", vswID, id)
+			status.VSwitchIds = append(status.VSwitchIds, vswID)
+		}
+	}
+
+	s.Info("reconcileNetworkAcl success", "status", status)
+	if len(repaired) > 0 {
+		s.markRepaired(infrav1.NetworkAclReadyCondition, "%s", strings.Join(repaired, "; "))
+		return nil
+	}
+	s.markAvailable(infrav1.NetworkAclReadyCondition)
+	return nil
+}
+
+// networkAclEntriesMatch compares the custom entries of the ACL with the spec, in order.
+func networkAclEntriesMatch(target *vpc.NetworkAclAttribute, spec *infrav1.NetworkAclSpec) bool {
+	var ingress, egress []infrav1.NetworkAclEntrySpec
+	for i := range target.IngressAclEntries.IngressAclEntry {
+		if e := &target.IngressAclEntries.IngressAclEntry[i]; e.EntryType == networkAclEntryCustom {
+			ingress = append(ingress, infrav1.NetworkAclEntryFromIngress(e))
+		}
+	}
+	for i := range target.EgressAclEntries.EgressAclEntry {
+		if e := &target.EgressAclEntries.EgressAclEntry[i]; e.EntryType == networkAclEntryCustom {
+			egress = append(egress, infrav1.NetworkAclEntryFromEgress(e))
+		}
+	}
+	return sameNetworkAclEntries(ingress, spec.IngressEntries) && sameNetworkAclEntries(egress, spec.EgressEntries)
+}
+
+// networkAclEntriesApplied tells whether the entries the provider last applied are the ones of the spec.
+func networkAclEntriesApplied(status *infrav1.NetworkAcl, spec *infrav1.NetworkAclSpec) bool {
+	return status.EntriesApplied &&
+		sameNetworkAclEntries(status.IngressEntries, spec.IngressEntries) &&
+		sameNetworkAclEntries(status.EgressEntries, spec.EgressEntries)
+}
+
+func normalizeNetworkAclEntries(entries []infrav1.NetworkAclEntrySpec) []infrav1.NetworkAclEntrySpec {
+	var list []infrav1.NetworkAclEntrySpec
+	for i := range entries {
+		list = append(list, entries[i].Normalize())
+	}
+	return list
+}
+
+func sameNetworkAclEntries(observed, desired []infrav1.NetworkAclEntrySpec) bool {
+	if len(observed) != len(desired) {
+		return false
+	}
+	for i := range desired {
+		if !reflect.DeepEqual(observed[i], desired[i].Normalize()) {
+			return false
+		}
+	}
+	return true
+}
+
+func networkAclBound(target *vpc.NetworkAclAttribute, vswitchID string) bool {
+	for _, r := range target.Resources.Resource {
+		if r.ResourceId == vswitchID {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteNetworkAcl disassociates the VSwitches the provider associated and deletes the network ACL
+// if the provider created it.
+func (s *ClusterProcessor) deleteNetworkAcl() error {
+	status := &s.alicloudCluster.Status.Network.NetworkAcl
+	id := status.NetworkAclId
+	if len(id) == 0 {
+		return nil
+	}
+
+	s.Info("deleteNetworkAcl")
+
+	target, err := s.networkAcl.Describe(id)
+	if err != nil {
+		return errors.Wrap(err, "Describe")
+	}
+	if target != nil {
+		for _, vswID := range status.VSwitchIds {
+			if !networkAclBound(target, vswID) {
+				continue
+			}
+			if err := s.networkAcl.Unassociate(id, vswID); err != nil {
+				s.warningf("FailedUnassociateNetworkAcl", err, "Failed to disassociate VSwitch %s from network ACL %s", vswID, id)
+				return errors.Wrap(err, "Unassociate")
+			}
+			if err := s.networkAcl.WaitUnassociated(id, vswID); err != nil {
+				return errors.Wrap(err, "WaitUnassociated")
+			}
+		}
+		status.VSwitchIds = nil
+
+		if s.owns(id) {
+			if err := s.networkAcl.Delete(id); err != nil {
+				s.warningf("FailedDeleteNetworkAcl", err, "Failed to delete network ACL %s", id)
+				return errors.Wrap(err, "Delete")
+			}
+			s.eventf("SuccessfulDeleteNetworkAcl", "Deleted network ACL %s", id)
+		} else {
+			s.Info("keep adopted network ACL", "id", id)
+		}
+	}
+
+	s.disown(id)
+	*status = infrav1.NetworkAcl{}
+	return nil
+}
@@ -0,0 +1,78 @@
+package controllers
+
+// own records that the provider created the resource id.
+func (s *ClusterProcessor) own(id string) {
+	network := &s.alicloudCluster.Status.Network
+	if len(id) > 0 && !contains(network.Owned, id) {
+		network.Owned = append(network.Owned, id)
+	}
+}
+
+// owns reports whether the provider created the resource id and may release it.
+func (s *ClusterProcessor) owns(id string) bool {
+	return len(id) > 0 && contains(s.alicloudCluster.Status.Network.Owned, id)
+}
+
+// disown forgets a resource once it is released.
+func (s *ClusterProcessor) disown(id string) {
+	network := &s.alicloudCluster.Status.Network
+	network.Owned = filter(network.Owned, id)
+}
+
+// recordOwnership fills Status.Network.Owned of a cluster created before ownership was recorded:
+// a resource not given by ID in the spec was created by the provider. NAT entries of an adopted
+// NAT gateway may have been adopted as well, so they are only owned along with the gateway.
+func (s *ClusterProcessor) recordOwnership() {
+	network := &s.alicloudCluster.Status.Network
+	if network.OwnershipRecorded {
+		return
+	}
+	spec := &s.alicloudCluster.Spec.Network
+
+	ownUnlessAdopted := func(id, specID string) {
+		if len(specID) == 0 {
+			s.own(id)
+		}
+	}
+	ownUnlessAdopted(network.VPC.VpcId, spec.VPC.VpcId)
+	ownUnlessAdopted(network.VSwitch.VSwitchId, spec.VSwitch.VSwitchId)
+	ownUnlessAdopted(network.Nat.NatGateway.NatGatewayId, spec.Nat.NatGateway.NatGatewayId)
+	ownUnlessAdopted(network.Nat.EIP.AllocationId, spec.Nat.EIP.AllocationId)
+	ownUnlessAdopted(network.SLB.LoadBalancerId, spec.SLB.LoadBalancerId)
+	ownUnlessAdopted(network.SLB.VServerGroupId, spec.SLB.VServerGroupId)
+	for _, sg := range s.securityGroups() {
+		ownUnlessAdopted(sg.status.SecurityGroupId, sg.spec.SecurityGroupId)
+	}
+
+	var pool []string
+	for _, eip := range spec.Nat.EIPs {
+		pool = append(pool, eip.AllocationId)
+	}
+	for _, eip := range network.Nat.EIPs {
+		if !contains(pool, eip.AllocationId) {
+			s.own(eip.AllocationId)
+		}
+	}
+	if pkg := spec.Nat.CommonBandwidthPackage; pkg == nil || len(pkg.BandwidthPackageId) == 0 {
+		s.own(network.Nat.CommonBandwidthPackage.BandwidthPackageId)
+	}
+
+	if s.owns(network.Nat.NatGateway.NatGatewayId) {
+		s.own(network.Nat.SnatEntryId)
+		for _, e := range network.Nat.SnatEntries {
+			s.own(e.SnatEntryId)
+		}
+		for _, e := range network.Nat.ForwardEntries {
+			s.own(e.ForwardEntryId)
+		}
+	}
+
+	// these have always been created by the provider
+	s.own(network.ExternalSLB.LoadBalancerId)
+	s.own(network.ExternalSLB.VServerGroupId)
+	s.own(network.ExternalEIP.AllocationId)
+	s.own(s.alicloudCluster.Status.Bastion.InstanceId)
+	s.own(s.alicloudCluster.Status.Bastion.EIP.AllocationId)
+
+	network.OwnershipRecorded = true
+}
@@ -0,0 +1,240 @@
+package controllers
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-alicloud/api/v1alpha2"
+	"sigs.k8s.io/cluster-api-provider-alicloud/pkg/aliyun"
+)
+
+const (
+	defaultZoneRecordName = "api"
+	defaultZoneRecordTTL  = 60
+)
+
+// reconcilePrivateZone creates or adopts the PrivateZone zone of Spec.Network.APIServer.PrivateZone, binds
+// it to the cluster VPC and points its API server record at the SLB address. Removing the spec only takes
+// effect when the cluster is deleted.
+func (s *ClusterProcessor) reconcilePrivateZone() error {
+	spec := s.alicloudCluster.Spec.Network.APIServer.PrivateZone
+	if spec == nil {
+		return nil
+	}
+
+	s.Info("reconcilePrivateZone")
+
+	network := &s.alicloudCluster.Status.Network
+	status := &network.PrivateZone
+	address := network.SLB.Address
+	if len(address) == 0 {
+		return errors.New("SLB address not allocated yet")
+	}
+
+	var repaired []string
+	id := status.ZoneId
+	if len(id) == 0 {
+		id = spec.ZoneId
+	}
+	if len(id) > 0 {
+		target, err := s.privateZone.Describe(id)
+		if err != nil {
+			return errors.Wrapf(err, "Describe %v", id)
+		}
+		if target == nil {
+			if len(status.ZoneId) == 0 {
+				return errors.Errorf("target not found: %v", id)
+			}
+			if !s.owns(id) || !s.autoRepair() {
+				s.markDrifted(infrav1.PrivateZoneReadyCondition, "PrivateZone %s no longer exists", id)
+				return nil
+			}
+			repaired = append(repaired, "Replaced missing PrivateZone "+id)
+			s.disown(id)
+			s.disown(status.RecordId)
+			*status = infrav1.PrivateZone{}
+			id = ""
+		}
+	}
+	if len(id) == 0 {
+		if len(spec.ZoneName) == 0 {
+			return errors.New("privateZone requires zoneId or zoneName")
+		}
+		var err error
+		id, err = s.privateZone.Create(spec.ZoneName)
+		if err != nil {
+			s.warningf("FailedCreatePrivateZone", err, "Failed to create PrivateZone %s", spec.ZoneName)
+			return errors.Wrap(err, "Create")
+		}
+		s.eventf("SuccessfulCreatePrivateZone", "Created PrivateZone %s (%s)", spec.ZoneName, id)
+		s.own(id)
+	}
+	zone, err := s.privateZone.Describe(id)
+	if err != nil {
+		return errors.Wrapf(err, "Describe %v", id)
+	}
+	if zone == nil {
+		return errors.Errorf("target not found: %v", id)
+	}
+	status.ZoneId = zone.ZoneId
+	status.ZoneName = zone.ZoneName
+	_ = s.patch()
+
+	vpcID := network.VPC.VpcId
+	var vpcIDs []string
+	for _, v := range zone.BindVpcs.Vpc {
+		vpcIDs = append(vpcIDs, v.VpcId)
+	}
+	if !contains(vpcIDs, vpcID) {
+		if status.VpcBound && !s.autoRepair() {
+			s.markDrifted(infrav1.PrivateZoneReadyCondition, "VPC %s is no longer bound to PrivateZone %s", vpcID, id)
+			return nil
+		}
+		if err := s.privateZone.BindVpcs(id, append(vpcIDs, vpcID)); err != nil {
+			s.warningf("FailedBindPrivateZone", err, "Failed to bind VPC %s to PrivateZone %s", vpcID, id)
+			return errors.Wrap(err, "BindVpcs")
+		}
+		if status.VpcBound {
+			repaired = append(repaired, "Bound VPC "+vpcID+" to PrivateZone "+id+" again")
+		} else {
+			s.eventf("SuccessfulBindPrivateZone", "Bound VPC %s to PrivateZone %s", vpcID, id)
+			status.VpcBound = true
+		}
+	}
+
+	if err := s.reconcileZoneRecord(spec, address, &repaired); err != nil {
+		return errors.Wrap(err, "reconcileZoneRecord")
+	}
+
+	s.Info("reconcilePrivateZone success", "status", status)
+	if len(repaired) > 0 {
+		s.markRepaired(infrav1.PrivateZoneReadyCondition, "%s", strings.Join(repaired, "; "))
+		return nil
+	}
+	s.markAvailable(infrav1.PrivateZoneReadyCondition)
+	return nil
+}
+
+// reconcileZoneRecord keeps the A record of the API server pointing at address. An existing record with
+// the same name in an adopted zone is taken over but not deleted with the cluster.
+func (s *ClusterProcessor) reconcileZoneRecord(spec *infrav1.PrivateZoneSpec, address string, repaired *[]string) error {
+	status := &s.alicloudCluster.Status.Network.PrivateZone
+	rr := spec.RecordName
+	if len(rr) == 0 {
+		rr = defaultZoneRecordName
+	}
+	ttl := spec.TTL
+	if ttl == 0 {
+		ttl = defaultZoneRecordTTL
+	}
+
+	if len(status.RecordId) > 0 && status.Rr != rr {
+		if s.owns(status.RecordId) {
+			if err := s.privateZone.DeleteRecord(status.RecordId); err != nil {
+				return errors.Wrap(err, "DeleteRecord")
+			}
+			s.eventf("SuccessfulDeleteZoneRecord", "Deleted record %s.%s", status.Rr, status.ZoneName)
+		}
+		s.disown(status.RecordId)
+		status.RecordId = ""
+	}
+
+	record, err := s.privateZone.DescribeRecord(status.ZoneId, rr, aliyun.ZoneRecordTypeA)
+	if err != nil {
+		return errors.Wrap(err, "DescribeRecord")
+	}
+	switch {
+	case record == nil:
+		if len(status.RecordId) > 0 && !s.autoRepair() {
+			s.markDrifted(infrav1.PrivateZoneReadyCondition, "Record %s.%s no longer exists", rr, status.ZoneName)
+			return nil
+		}
+		id, err := s.privateZone.CreateRecord(status.ZoneId, rr, aliyun.ZoneRecordTypeA, address, ttl)
+		if err != nil {
+			s.warningf("FailedCreateZoneRecord", err, "Failed to create record %s.%s", rr, status.ZoneName)
+			return errors.Wrap(err, "CreateRecord")
+		}
+		if len(status.RecordId) > 0 {
+			*repaired = append(*repaired, "Recreated record "+rr+"."+status.ZoneName)
+			s.disown(status.RecordId)
+		} else {
+			s.eventf("SuccessfulCreateZoneRecord", "Created record %s.%s pointing at %s", rr, status.ZoneName, address)
+		}
+		s.own(id)
+		status.RecordId = id
+	case record.Value != address || record.Ttl != ttl:
+		id := record.RecordId
+		if err := s.privateZone.UpdateRecord(strconv.Itoa(id), rr, aliyun.ZoneRecordTypeA, address, ttl); err != nil {
+			s.warningf("FailedUpdateZoneRecord", err, "Failed to update record %s.%s", rr, status.ZoneName)
+			return errors.Wrap(err, "UpdateRecord")
+		}
+		s.eventf("SuccessfulUpdateZoneRecord", "Pointed record %s.%s at %s", rr, status.ZoneName, address)
+		status.RecordId = strconv.Itoa(id)
+	default:
+		status.RecordId = strconv.Itoa(record.RecordId)
+	}
+	status.Rr = rr
+	status.Value = address
+	return nil
+}
+
+// deletePrivateZone deletes the API server record and the zone if the provider created them, and unbinds
+// the cluster VPC from an adopted zone.
+func (s *ClusterProcessor) deletePrivateZone() error {
+	status := &s.alicloudCluster.Status.Network.PrivateZone
+	id := status.ZoneId
+	if len(id) == 0 {
+		return nil
+	}
+
+	s.Info("deletePrivateZone")
+
+	if len(status.RecordId) > 0 && s.owns(status.RecordId) {
+		if err := s.privateZone.DeleteRecord(status.RecordId); err != nil {
+			s.warningf("FailedDeleteZoneRecord", err, "Failed to delete record %s.%s", status.Rr, status.ZoneName)
+			return errors.Wrap(err, "DeleteRecord")
+		}
+		s.eventf("SuccessfulDeleteZoneRecord", "Deleted record %s.%s", status.Rr, status.ZoneName)
+	}
+	s.disown(status.RecordId)
+	status.RecordId = ""
+
+	zone, err := s.privateZone.Describe(id)
+	if err != nil {
+		return errors.Wrap(err, "Describe")
+	}
+	if zone != nil {
+		vpcID := s.alicloudCluster.Status.Network.VPC.VpcId
+		var others []string
+		bound := false
+		for _, v := range zone.BindVpcs.Vpc {
+			if v.VpcId == vpcID {
+				bound = true
+				continue
+			}
+			others = append(others, v.VpcId)
+		}
+		if bound && status.VpcBound {
+			if err := s.privateZone.BindVpcs(id, others); err != nil {
+				s.warningf("FailedUnbindPrivateZone", err, "Failed to unbind VPC %s from PrivateZone %s", vpcID, id)
+				return errors.Wrap(err, "BindVpcs")
+			}
+		}
+		status.VpcBound = false
+
+		if s.owns(id) {
+			if err := s.privateZone.Delete(id); err != nil {
+				s.warningf("FailedDeletePrivateZone", err, "Failed to delete PrivateZone %s", id)
+				return errors.Wrap(err, "Delete")
+			}
+			s.eventf("SuccessfulDeletePrivateZone", "Deleted PrivateZone %s", id)
+		} else {
+			s.Info("keep adopted PrivateZone", "id", id)
+		}
+	}
+
+	s.disown(id)
+	*status = infrav1.PrivateZone{}
+	return nil
+}
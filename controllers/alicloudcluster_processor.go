@@ -3,11 +3,15 @@ package controllers
 import (
 	"context"
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+	"reflect"
 	"sigs.k8s.io/cluster-api-provider-alicloud/pkg"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
 	infrav1 "sigs.k8s.io/cluster-api-provider-alicloud/api/v1alpha2"
 	"sigs.k8s.io/cluster-api-provider-alicloud/pkg/aliyun"
 	"sigs.k8s.io/cluster-api-provider-alicloud/pkg/aliyun/retry"
@@ -22,6 +26,7 @@ type ClusterProcessor struct {
 	logr.Logger
 
 	client          client.Client
+	recorder        record.EventRecorder
 	patchHelper     *patch.Helper
 	cluster         *clusterv1.Cluster
 	alicloudCluster *infrav1.AlicloudCluster
@@ -30,12 +35,18 @@ type ClusterProcessor struct {
 	vpc           *aliyun.VPCClient
 	vswitch       *aliyun.VSwitchClient
 	securityGroup *aliyun.SecurityGroupClient
+	bastion       *aliyun.BastionClient
+	routeTable    *aliyun.RouteTableClient
+	networkAcl    *aliyun.NetworkAclClient
+	privateZone   *aliyun.PrivateZoneClient
+	cen           *aliyun.CENClient
 }
 
 func NewClusterProcessor(
 	logger logr.Logger,
 	regionID string,
 	client client.Client,
+	recorder record.EventRecorder,
 	cluster *clusterv1.Cluster,
 	alicloudCluster *infrav1.AlicloudCluster,
 ) (*ClusterProcessor, error) {
@@ -60,11 +71,32 @@ func NewClusterProcessor(
 	if err != nil {
 		return nil, errors.Wrap(err, "NewSecurityGroupClient")
 	}
+	bastionCli, err := aliyun.NewBastionClient(logger, regionID)
+	if err != nil {
+		return nil, errors.Wrap(err, "NewBastionClient")
+	}
+	routeTableCli, err := aliyun.NewRouteTableClient(logger, regionID)
+	if err != nil {
+		return nil, errors.Wrap(err, "NewRouteTableClient")
+	}
+	networkAclCli, err := aliyun.NewNetworkAclClient(logger, regionID)
+	if err != nil {
+		return nil, errors.Wrap(err, "NewNetworkAclClient")
+	}
+	privateZoneCli, err := aliyun.NewPrivateZoneClient(logger, regionID)
+	if err != nil {
+		return nil, errors.Wrap(err, "NewPrivateZoneClient")
+	}
+	cenCli, err := aliyun.NewCENClient(logger, regionID)
+	if err != nil {
+		return nil, errors.Wrap(err, "NewCENClient")
+	}
 
 	return &ClusterProcessor{
 		Logger: logger,
 
 		client:          client,
+		recorder:        recorder,
 		patchHelper:     helper,
 		cluster:         cluster,
 		alicloudCluster: alicloudCluster,
@@ -73,6 +105,11 @@ func NewClusterProcessor(
 		vpc:           vpcCli,
 		vswitch:       vswitchCli,
 		securityGroup: securityGroupCli,
+		bastion:       bastionCli,
+		routeTable:    routeTableCli,
+		networkAcl:    networkAclCli,
+		privateZone:   privateZoneCli,
+		cen:           cenCli,
 	}, nil
 }
 
@@ -86,10 +123,29 @@ func (s *ClusterProcessor) patch() error {
 	return errors.Wrap(s.patchHelper.Patch(context.TODO(), s.alicloudCluster), "patchHelper.Patch")
 }
 
+func (s *ClusterProcessor) eventf(reason, messageFmt string, args ...interface{}) {
+	s.recorder.Eventf(s.alicloudCluster, corev1.EventTypeNormal, reason, messageFmt, args...)
+}
+
+func (s *ClusterProcessor) warningf(reason string, err error, messageFmt string, args ...interface{}) {
+	recordWarning(s.recorder, s.alicloudCluster, reason, err, messageFmt, args...)
+}
+
 ///////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
 func (s *ClusterProcessor) ReconcileDelete() (reconcile.Result, error) {
 	s.Info("ReconcileDelete")
+	s.recordOwnership()
+
+	// Instances still attached to the VSwitch and security groups make their deletion fail with
+	// dependency errors, so the network is only dismantled once every machine is gone.
+	gone, err := s.machinesDeleted()
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "machinesDeleted")
+	}
+	if !gone {
+		return reconcile.Result{RequeueAfter: machineDeletionPollInterval}, nil
+	}
 
 	if rs, err := s.deleteNetwork(); err != nil {
 		return rs, errors.Wrap(err, "deleteNetwork")
@@ -110,18 +166,43 @@ func filter(list []string, strToFilter string) (newList []string) {
 	return
 }
 
+func contains(list []string, str string) bool {
+	for _, item := range list {
+		if item == str {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *ClusterProcessor) deleteNetwork() (reconcile.Result, error) {
 	s.Info("deleteNetwork")
 
+	if rs, err := s.deleteBastion(); err != nil {
+		return rs, errors.Wrap(err, "deleteBastion")
+	}
+
 	if rs, err := s.deleteSecurityGroup(); err != nil {
 		return rs, errors.Wrap(err, "deleteSecurityGroup")
 	}
+	if rs, err := s.deleteAPIServerAccess(); err != nil {
+		return rs, errors.Wrap(err, "deleteAPIServerAccess")
+	}
 	if rs, err := s.deleteSLB(); err != nil {
 		return rs, errors.Wrap(err, "deleteSLB")
 	}
 	if rs, err := s.deleteNat(); err != nil {
 		return rs, errors.Wrap(err, "deleteNat")
 	}
+	if err := s.deleteCEN(); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "deleteCEN")
+	}
+	if err := s.deleteNetworkAcl(); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "deleteNetworkAcl")
+	}
+	if err := s.deleteRouteTable(); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "deleteRouteTable")
+	}
 	if rs, err := s.deleteVSwitch(); err != nil {
 		return rs, errors.Wrap(err, "deleteVSwitch")
 	}
@@ -136,10 +217,53 @@ func (s *ClusterProcessor) deleteNetwork() (reconcile.Result, error) {
 func (s *ClusterProcessor) deleteSecurityGroup() (reconcile.Result, error) {
 	s.Info("deleteSecurityGroup")
 
-	id := s.alicloudCluster.Status.Network.SecurityGroup.SecurityGroupId
+	groups := s.securityGroups()
+
+	// a group cannot be deleted while a rule of another group still references it
+	for _, sg := range groups {
+		if err := s.revokeSecurityGroupReferences(sg); err != nil {
+			return reconcile.Result{}, errors.Wrapf(err, "revokeSecurityGroupReferences %v", sg.role)
+		}
+	}
+
+	for _, sg := range groups {
+		if rs, err := s.deleteSecurityGroupOf(sg); err != nil {
+			return rs, errors.Wrapf(err, "deleteSecurityGroup %v", sg.role)
+		}
+	}
+	return reconcile.Result{}, nil
+}
+
+func (s *ClusterProcessor) revokeSecurityGroupReferences(sg securityGroupScope) error {
+	id := sg.status.SecurityGroupId
+	for i := range sg.status.Rules {
+		rule := &sg.status.Rules[i]
+		if (len(rule.SourceGroupId) == 0 || rule.SourceGroupId == id) && (len(rule.DestGroupId) == 0 || rule.DestGroupId == id) {
+			continue
+		}
+		if err := s.securityGroup.RevokeRule(id, rule); err != nil {
+			return errors.Wrapf(err, "RevokeRule %v", rule.Key())
+		}
+	}
+	return nil
+}
+
+func (s *ClusterProcessor) deleteSecurityGroupOf(sg securityGroupScope) (reconcile.Result, error) {
+	id := sg.status.SecurityGroupId
 	if len(id) == 0 {
 		return reconcile.Result{}, nil
 	}
+	if !s.owns(id) {
+		// an adopted group stays, only the rules the provider added are revoked
+		s.Info("revoke rules of adopted security group", "role", sg.role, "id", id)
+		for i := range sg.status.Rules {
+			if err := s.securityGroup.RevokeRule(id, &sg.status.Rules[i]); err != nil {
+				return reconcile.Result{}, errors.Wrapf(err, "RevokeRule %v", sg.status.Rules[i].Key())
+			}
+		}
+		sg.status.Rules = nil
+		return reconcile.Result{}, nil
+	}
 
 	target, err := s.securityGroup.Describe(id)
 	if err != nil {
@@ -151,8 +275,10 @@ func (s *ClusterProcessor) deleteSecurityGroup() (reconcile.Result, error) {
 
 	err = s.securityGroup.Delete(id)
 	if err != nil {
+		s.warningf("FailedDeleteSecurityGroup", err, "Failed to delete %s security group %s", sg.role, id)
 		return reconcile.Result{}, errors.Wrap(err, "deleteSecurityGroup")
 	}
+	s.eventf("SuccessfulDeleteSecurityGroup", "Deleted %s security group %s", sg.role, id)
 
 	return reconcile.Result{}, retry.Try(retry.DefaultBackOf, func() error {
 		target, err := s.securityGroup.Describe(id)
@@ -182,10 +308,30 @@ func (s *ClusterProcessor) deleteSLB() (reconcile.Result, error) {
 		return reconcile.Result{}, nil
 	}
 
+	if !s.owns(id) {
+		// an adopted SLB stays, only the listener and VServer group the provider added are removed
+		if err := s.slb.DeleteListener(id); err != nil {
+			s.warningf("FailedDeleteListener", err, "Failed to delete TCP listener on SLB %s", id)
+			return reconcile.Result{}, errors.Wrap(err, "DeleteListener")
+		}
+		s.eventf("SuccessfulDeleteListener", "Deleted TCP listener on SLB %s", id)
+
+		if vsgID := s.alicloudCluster.Status.Network.SLB.VServerGroupId; s.owns(vsgID) {
+			if err := s.slb.DeleteServerGroup(vsgID); err != nil {
+				s.warningf("FailedDeleteVServerGroup", err, "Failed to delete VServer group %s on SLB %s", vsgID, id)
+				return reconcile.Result{}, errors.Wrap(err, "DeleteServerGroup")
+			}
+			s.eventf("SuccessfulDeleteVServerGroup", "Deleted VServer group %s on SLB %s", vsgID, id)
+		}
+		return reconcile.Result{}, nil
+	}
+
 	err = s.slb.Delete(id)
 	if err != nil {
+		s.warningf("FailedDeleteSLB", err, "Failed to delete SLB %s", id)
 		return reconcile.Result{}, errors.Wrap(err, "deleteSLB")
 	}
+	s.eventf("SuccessfulDeleteSLB", "Deleted SLB %s", id)
 
 	return reconcile.Result{}, retry.Try(retry.DefaultBackOf, func() error {
 		target, err := s.slb.Describe(id)
@@ -207,11 +353,17 @@ func (s *ClusterProcessor) deleteNat() (reconcile.Result, error) {
 		return reconcile.Result{}, nil
 	}
 
-	if len(s.alicloudCluster.Status.Network.Nat.SnatEntryId) > 0 {
-		s.Info("DeleteSnatEntry")
-		if err := s.vpc.DeleteSnatEntry(&s.alicloudCluster.Status.Network.Nat); err != nil {
-			return reconcile.Result{}, errors.Wrap(err, "DeleteSnatEntry")
-		}
+	s.Info("deleteNatEntries")
+	if err := s.deleteNatEntries(); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "deleteNatEntries")
+	}
+	s.Info("deleteBandwidthPackage")
+	if err := s.deleteBandwidthPackage(); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "deleteBandwidthPackage")
+	}
+	s.Info("deleteEIPPool")
+	if err := s.deleteEIPPool(); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "deleteEIPPool")
 	}
 
 	if rs, err := s.deleteEIP(); err != nil {
@@ -239,19 +391,28 @@ func (s *ClusterProcessor) deleteEIP() (reconcile.Result, error) {
 		return reconcile.Result{}, nil
 	}
 
-	if target.Status == infrav1.EIPInUse {
+	ngwID := s.alicloudCluster.Status.Network.Nat.NatGateway.NatGatewayId
+	if target.Status == infrav1.EIPInUse && target.InstanceId == ngwID && (s.owns(id) || s.owns(ngwID)) {
 		if err := s.vpc.UnassociateEipToNatGateway(&s.alicloudCluster.Status.Network.Nat.EIP, &s.alicloudCluster.Status.Network.Nat.NatGateway); err != nil {
+			s.warningf("FailedUnassociateEIP", err, "Failed to unassociate EIP %s from NAT gateway %s", id, s.alicloudCluster.Status.Network.Nat.NatGateway.NatGatewayId)
 			return reconcile.Result{}, errors.Wrap(err, "UnassociateEipToNatGateway")
 		}
+		s.eventf("SuccessfulUnassociateEIP", "Unassociated EIP %s from NAT gateway %s", id, s.alicloudCluster.Status.Network.Nat.NatGateway.NatGatewayId)
 		if _, err = s.vpc.WaitEIPStatus(target.AllocationId, infrav1.Available); err != nil {
 			return reconcile.Result{}, errors.Wrap(err, "WaitEIPStatus Available")
 		}
 	}
+	if !s.owns(id) {
+		s.Info("keep adopted EIP", "id", id)
+		return reconcile.Result{}, nil
+	}
 
 	err = s.vpc.DeleteEIP(id)
 	if err != nil {
-		return reconcile.Result{}, errors.Wrap(err, "deleteVSwitch")
+		s.warningf("FailedDeleteEIP", err, "Failed to delete EIP %s", id)
+		return reconcile.Result{}, errors.Wrap(err, "DeleteEIP")
 	}
+	s.eventf("SuccessfulDeleteEIP", "Deleted EIP %s", id)
 
 	return reconcile.Result{}, retry.Try(retry.DefaultBackOf, func() error {
 		target, err := s.vpc.DescribeEIP(id)
@@ -272,6 +433,10 @@ func (s *ClusterProcessor) deleteNatGateway() (reconcile.Result, error) {
 	if len(id) == 0 {
 		return reconcile.Result{}, nil
 	}
+	if !s.owns(id) {
+		s.Info("keep adopted NAT gateway", "id", id)
+		return reconcile.Result{}, nil
+	}
 
 	target, err := s.vpc.DescribeNatGateway(id)
 	if err != nil {
@@ -283,8 +448,10 @@ func (s *ClusterProcessor) deleteNatGateway() (reconcile.Result, error) {
 
 	err = s.vpc.DeleteGateway(id)
 	if err != nil {
-		return reconcile.Result{}, errors.Wrap(err, "deleteVSwitch")
+		s.warningf("FailedDeleteNatGateway", err, "Failed to delete NAT gateway %s", id)
+		return reconcile.Result{}, errors.Wrap(err, "DeleteGateway")
 	}
+	s.eventf("SuccessfulDeleteNatGateway", "Deleted NAT gateway %s", id)
 
 	return reconcile.Result{}, retry.Try(retry.DefaultBackOf, func() error {
 		target, err := s.vpc.DescribeNatGateway(id)
@@ -305,6 +472,10 @@ func (s *ClusterProcessor) deleteVSwitch() (reconcile.Result, error) {
 	if len(id) == 0 {
 		return reconcile.Result{}, nil
 	}
+	if !s.owns(id) {
+		s.Info("keep adopted VSwitch", "id", id)
+		return reconcile.Result{}, nil
+	}
 
 	target, err := s.vswitch.Describe(id)
 	if err != nil {
@@ -316,8 +487,10 @@ func (s *ClusterProcessor) deleteVSwitch() (reconcile.Result, error) {
 
 	err = s.vswitch.Delete(id)
 	if err != nil {
+		s.warningf("FailedDeleteVSwitch", err, "Failed to delete VSwitch %s", id)
 		return reconcile.Result{}, errors.Wrap(err, "deleteVSwitch")
 	}
+	s.eventf("SuccessfulDeleteVSwitch", "Deleted VSwitch %s", id)
 
 	return reconcile.Result{}, retry.Try(retry.DefaultBackOf, func() error {
 		target, err := s.vswitch.Describe(id)
@@ -338,6 +511,10 @@ func (s *ClusterProcessor) deleteVPC() (reconcile.Result, error) {
 	if len(id) == 0 {
 		return reconcile.Result{}, nil
 	}
+	if !s.owns(id) {
+		s.Info("keep adopted VPC", "id", id)
+		return reconcile.Result{}, nil
+	}
 
 	target, err := s.vpc.Describe(id)
 	if err != nil {
@@ -349,8 +526,10 @@ func (s *ClusterProcessor) deleteVPC() (reconcile.Result, error) {
 
 	err = s.vpc.Delete(id)
 	if err != nil {
+		s.warningf("FailedDeleteVPC", err, "Failed to delete VPC %s", id)
 		return reconcile.Result{}, errors.Wrap(err, "deleteVPC")
 	}
+	s.eventf("SuccessfulDeleteVPC", "Deleted VPC %s", id)
 
 	return reconcile.Result{}, retry.Try(retry.DefaultBackOf, func() error {
 		target, err := s.vpc.Describe(id)
@@ -367,8 +546,14 @@ func (s *ClusterProcessor) deleteVPC() (reconcile.Result, error) {
 ///////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
 func (s *ClusterProcessor) ReconcileNormal() (reconcile.Result, error) {
+	s.recordOwnership()
+
 	if s.alicloudCluster.Status.Ready {
-		return reconcile.Result{}, nil
+		if rs, err := s.verifyNetwork(); err != nil {
+			return rs, err
+		}
+		// endpoints added to the spec after the cluster became ready are published right away
+		return reconcile.Result{}, s.syncAPIEndpoints()
 	}
 
 	s.Info("ReconcileNormal")
@@ -377,15 +562,8 @@ func (s *ClusterProcessor) ReconcileNormal() (reconcile.Result, error) {
 		s.alicloudCluster.Finalizers = append(s.alicloudCluster.Finalizers, infrav1.ClusterFinalizer)
 	}
 
-	if len(s.cluster.Status.APIEndpoints) != len(s.alicloudCluster.Status.ApiEndpoints) {
-		clusterPatcher, err := patch.NewHelper(s.cluster, s.client)
-		if err != nil {
-			return reconcile.Result{}, errors.Wrap(err, "patch.NewHelper error")
-		}
-		s.cluster.Status.APIEndpoints = s.alicloudCluster.Status.ApiEndpoints
-		if err := clusterPatcher.Patch(context.TODO(), s.cluster); err != nil {
-			return reconcile.Result{}, errors.Wrap(err, "clusterPatcher.Patch error")
-		}
+	if err := s.syncAPIEndpoints(); err != nil {
+		return reconcile.Result{}, err
 	}
 
 	if rs, err := s.reconcileNetwork(); err != nil {
@@ -396,6 +574,19 @@ func (s *ClusterProcessor) ReconcileNormal() (reconcile.Result, error) {
 	return reconcile.Result{}, nil
 }
 
+// syncAPIEndpoints copies the API endpoints of the AlicloudCluster to the Cluster.
+func (s *ClusterProcessor) syncAPIEndpoints() error {
+	if reflect.DeepEqual(s.cluster.Status.APIEndpoints, s.alicloudCluster.Status.ApiEndpoints) {
+		return nil
+	}
+	clusterPatcher, err := patch.NewHelper(s.cluster, s.client)
+	if err != nil {
+		return errors.Wrap(err, "patch.NewHelper error")
+	}
+	s.cluster.Status.APIEndpoints = s.alicloudCluster.Status.ApiEndpoints
+	return errors.Wrap(clusterPatcher.Patch(context.TODO(), s.cluster), "clusterPatcher.Patch error")
+}
+
 func (s *ClusterProcessor) reconcileNetwork() (reconcile.Result, error) {
 	s.Info("reconcileNetwork")
 	s.alicloudCluster.Status.Message = "reconcileNetwork"
@@ -408,22 +599,58 @@ func (s *ClusterProcessor) reconcileNetwork() (reconcile.Result, error) {
 	if rs, err := s.reconcileVSwitch(); err != nil {
 		return rs, errors.Wrap(err, "reconcileVSwitch")
 	}
+	s.alicloudCluster.Status.Message += "-reconcileRouteTable"
+	if err := s.reconcileRouteTable(); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "reconcileRouteTable")
+	}
+	s.alicloudCluster.Status.Message += "-reconcileNetworkAcl"
+	if err := s.reconcileNetworkAcl(); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "reconcileNetworkAcl")
+	}
+	s.alicloudCluster.Status.Message += "-reconcileCEN"
+	if err := s.reconcileCEN(); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "reconcileCEN")
+	}
 	s.alicloudCluster.Status.Message += "-reconcileNat"
 	if rs, err := s.reconcileNat(); err != nil {
 		return rs, errors.Wrap(err, "reconcileNat")
 	}
+	s.alicloudCluster.Status.Message += "-reconcileEIPPool"
+	if err := s.reconcileEIPPool(); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "reconcileEIPPool")
+	}
+	s.alicloudCluster.Status.Message += "-reconcileBandwidthPackage"
+	if err := s.reconcileBandwidthPackage(); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "reconcileBandwidthPackage")
+	}
+	s.alicloudCluster.Status.Message += "-reconcileNatEntries"
+	if err := s.reconcileNatEntries(); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "reconcileNatEntries")
+	}
 	s.alicloudCluster.Status.Message += "-reconcileSLB"
 	if rs, err := s.reconcileSLB(); err != nil {
 		return rs, errors.Wrap(err, "reconcileSLB")
 	}
+	s.alicloudCluster.Status.Message += "-reconcileAPIServerAccess"
+	if rs, err := s.reconcileAPIServerAccess(); err != nil {
+		return rs, errors.Wrap(err, "reconcileAPIServerAccess")
+	}
 	s.alicloudCluster.Status.Message += "-reconcileSecurityGroup"
 	if rs, err := s.reconcileSecurityGroup(); err != nil {
 		return rs, errors.Wrap(err, "reconcileSecurityGroup")
 	}
+	s.alicloudCluster.Status.Message += "-reconcileSecurityGroupRules"
+	if rs, err := s.reconcileSecurityGroupRules(); err != nil {
+		return rs, errors.Wrap(err, "reconcileSecurityGroupRules")
+	}
 	s.alicloudCluster.Status.Message += "-reconcileSSHKey"
 	if rs, err := s.reconcileSSHKey(); err != nil {
 		return rs, errors.Wrap(err, "reconcileSSHKey")
 	}
+	s.alicloudCluster.Status.Message += "-reconcileBastion"
+	if rs, err := s.reconcileBastion(); err != nil {
+		return rs, errors.Wrap(err, "reconcileBastion")
+	}
 
 	s.Info("reconcileNetwork success")
 	return reconcile.Result{}, nil
@@ -436,7 +663,7 @@ func (s *ClusterProcessor) reconcileVPC() (reconcile.Result, error) {
 
 	s.Info("reconcileVPC")
 
-	spec := s.alicloudCluster.Spec.Network.VPC
+	spec := s.vpcSpec()
 	id := spec.VpcId
 
 	var err error
@@ -458,14 +685,21 @@ func (s *ClusterProcessor) reconcileVPC() (reconcile.Result, error) {
 	} else {
 		id, err = s.vpc.Create(spec)
 		if err != nil {
+			s.warningf("FailedCreateVPC", err, "Failed to create VPC")
 			return reconcile.Result{}, errors.Wrap(err, "Create")
 		}
+		s.eventf("SuccessfulCreateVPC", "Created VPC %s", id)
+		s.own(id)
 		target, err = s.vpc.WaitReady(id)
 		if err != nil {
 			return reconcile.Result{}, errors.Wrapf(err, "WaitRead %v", id)
 		}
 	}
 
+	if err := s.requireIPv6("VPC", target.VpcId, target.Ipv6CidrBlock); err != nil {
+		return reconcile.Result{}, err
+	}
+
 	s.Info("reconcileVPC success", "status", target)
 	target.DeepCopyInto(&s.alicloudCluster.Status.Network.VPC)
 	_ = s.patch()
@@ -479,7 +713,7 @@ func (s *ClusterProcessor) reconcileVSwitch() (reconcile.Result, error) {
 
 	s.Info("reconcileVSwitch")
 
-	spec := s.alicloudCluster.Spec.Network.VSwitch
+	spec := s.vswitchSpec()
 	id := spec.VSwitchId
 
 	var err error
@@ -499,16 +733,28 @@ func (s *ClusterProcessor) reconcileVSwitch() (reconcile.Result, error) {
 			}
 		}
 	} else {
+		spec.CidrBlock, err = s.vswitchCIDR(spec)
+		if err != nil {
+			s.warningf("FailedAllocateVSwitchCIDR", err, "Failed to allocate VSwitch CIDR block")
+			return reconcile.Result{}, errors.Wrap(err, "vswitchCIDR")
+		}
 		id, err = s.vswitch.Create(spec, s.alicloudCluster.Spec.ZoneId, s.alicloudCluster.Status.Network.VPC.VpcId)
 		if err != nil {
+			s.warningf("FailedCreateVSwitch", err, "Failed to create VSwitch")
 			return reconcile.Result{}, errors.Wrap(err, "Create")
 		}
+		s.eventf("SuccessfulCreateVSwitch", "Created VSwitch %s", id)
+		s.own(id)
 		target, err = s.vswitch.WaitReady(id)
 		if err != nil {
 			return reconcile.Result{}, errors.Wrapf(err, "WaitRead %v", id)
 		}
 	}
 
+	if err := s.requireIPv6("VSwitch", target.VSwitchId, target.Ipv6CidrBlock); err != nil {
+		return reconcile.Result{}, err
+	}
+
 	s.Info("reconcileVSwitch success", "status", target)
 	target.DeepCopyInto(&s.alicloudCluster.Status.Network.VSwitch)
 	_ = s.patch()
@@ -544,8 +790,11 @@ func (s *ClusterProcessor) reconcileNatGateway() (reconcile.Result, error) {
 	} else {
 		ngwID, err := s.vpc.CreateNatGateway(spec, s.alicloudCluster.Status.Network.VPC.VpcId)
 		if err != nil {
+			s.warningf("FailedCreateNatGateway", err, "Failed to create NAT gateway")
 			return reconcile.Result{}, errors.Wrap(err, "Create")
 		}
+		s.eventf("SuccessfulCreateNatGateway", "Created NAT gateway %s", ngwID)
+		s.own(ngwID)
 		target, err = s.vpc.WaitNatGatewayReady(ngwID)
 		if err != nil {
 			return reconcile.Result{}, errors.Wrapf(err, "WaitRead %v", id)
@@ -587,8 +836,11 @@ func (s *ClusterProcessor) reconcileEIP() (reconcile.Result, error) {
 	} else {
 		eipID, err := s.vpc.CreateEIP(spec, s.alicloudCluster.Status.Network.VPC.VpcId)
 		if err != nil {
+			s.warningf("FailedCreateEIP", err, "Failed to allocate EIP")
 			return reconcile.Result{}, errors.Wrap(err, "Create")
 		}
+		s.eventf("SuccessfulCreateEIP", "Allocated EIP %s", eipID)
+		s.own(eipID)
 		target, err = s.vpc.WaitEIPStatus(eipID, infrav1.EIPAvailable)
 		if err != nil {
 			return reconcile.Result{}, errors.Wrapf(err, "WaitRead %v", id)
@@ -609,10 +861,17 @@ func (s *ClusterProcessor) reconcileSSHKey() (reconcile.Result, error) {
 		return reconcile.Result{}, errors.Wrap(err, "GetEcsClient")
 	}
 
+	limiter := aliyun.Limiter(s.alicloudCluster.Spec.RegionId, "ecs")
+
 	keyreq := ecs.CreateDescribeKeyPairsRequest()
 	keyreq.KeyPairName = pkg.DefaultSSHKeyName
 	keyreq.RegionId = s.alicloudCluster.Spec.RegionId
-	keyresp, err := ecscli.DescribeKeyPairs(keyreq)
+	var keyresp *ecs.DescribeKeyPairsResponse
+	err = aliyun.Call(limiter, "ecs", "DescribeKeyPairs", func() error {
+		var err error
+		keyresp, err = ecscli.DescribeKeyPairs(keyreq)
+		return err
+	})
 	if err != nil || keyresp == nil {
 		return reconcile.Result{Requeue: true, RequeueAfter: time.Second * 5}, nil
 	}
@@ -621,10 +880,17 @@ func (s *ClusterProcessor) reconcileSSHKey() (reconcile.Result, error) {
 		req := ecs.CreateCreateKeyPairRequest()
 		req.RegionId = s.alicloudCluster.Spec.RegionId
 		req.KeyPairName = pkg.DefaultSSHKeyName
-		resp, err := ecscli.CreateKeyPair(req)
+		var resp *ecs.CreateKeyPairResponse
+		err := aliyun.Call(limiter, "ecs", "CreateKeyPair", func() error {
+			var err error
+			resp, err = ecscli.CreateKeyPair(req)
+			return err
+		})
 		if err != nil {
+			s.warningf("FailedCreateKeyPair", err, "Failed to create key pair %s", pkg.DefaultSSHKeyName)
 			return reconcile.Result{}, errors.Wrap(err, "CreateKeyPair")
 		}
+		s.eventf("SuccessfulCreateKeyPair", "Created key pair %s", pkg.DefaultSSHKeyName)
 		s.Info("create default keypair", "resp", resp.KeyPairId)
 	}
 
@@ -654,8 +920,10 @@ func (s *ClusterProcessor) reconcileNat() (reconcile.Result, error) {
 	s.Info("AssociateEipToNatGateway")
 	s.alicloudCluster.Status.Message += "-AssociateEipToNatGateway"
 	if err = s.vpc.AssociateEipToNatGateway(eip, ngw); err != nil {
+		s.warningf("FailedAssociateEIP", err, "Failed to associate EIP %s with NAT gateway %s", eip.AllocationId, ngw.NatGatewayId)
 		return reconcile.Result{}, errors.Wrap(err, "AssociateEipToNatGateway")
 	}
+	s.eventf("SuccessfulAssociateEIP", "Associated EIP %s with NAT gateway %s", eip.AllocationId, ngw.NatGatewayId)
 
 	s.Info("WaitEIPStatus")
 	s.alicloudCluster.Status.Message += "-WaitEIPStatus"
@@ -664,15 +932,8 @@ func (s *ClusterProcessor) reconcileNat() (reconcile.Result, error) {
 		return reconcile.Result{}, errors.Wrap(err, "WaitEIPStatus")
 	}
 
-	s.Info("CreateSnatEntry")
-	s.alicloudCluster.Status.Message += "-CreateSnatEntry"
-	snatEntryId, err := s.vpc.CreateSnatEntry(eip, ngw, s.alicloudCluster.Status.Network.VSwitch.VSwitchId)
-	if err != nil {
-		return reconcile.Result{}, errors.Wrap(err, "CreateSnatEntry")
-	}
-
 	s.Info("reconcileNat success")
-	s.alicloudCluster.Status.Network.Nat.SnatEntryId = snatEntryId
+	eip.DeepCopyInto(&s.alicloudCluster.Status.Network.Nat.EIP)
 	_ = s.patch()
 	return reconcile.Result{}, nil
 }
@@ -684,7 +945,7 @@ func (s *ClusterProcessor) reconcileSLB() (reconcile.Result, error) {
 
 	s.Info("reconcileSLB")
 
-	spec := s.alicloudCluster.Spec.Network.SLB
+	spec := s.slbSpec()
 	id := spec.LoadBalancerId
 
 	var err error
@@ -706,8 +967,11 @@ func (s *ClusterProcessor) reconcileSLB() (reconcile.Result, error) {
 	} else {
 		id, err = s.slb.Create(spec, s.alicloudCluster.Status.Network.VPC.VpcId)
 		if err != nil {
+			s.warningf("FailedCreateSLB", err, "Failed to create SLB")
 			return reconcile.Result{}, errors.Wrap(err, "Create")
 		}
+		s.eventf("SuccessfulCreateSLB", "Created SLB %s", id)
+		s.own(id)
 		target, err = s.slb.WaitReady(id)
 		if err != nil {
 			return reconcile.Result{}, errors.Wrapf(err, "WaitRead %v", id)
@@ -733,40 +997,49 @@ func (s *ClusterProcessor) reconcileSLB() (reconcile.Result, error) {
 	} else {
 		vsgID, err := s.slb.CreateServerGroup(spec, id)
 		if err != nil {
+			s.warningf("FailedCreateVServerGroup", err, "Failed to create VServer group on SLB %s", id)
 			return reconcile.Result{}, errors.Wrapf(err, "CreateServerGroup %v", id)
 		}
+		s.eventf("SuccessfulCreateVServerGroup", "Created VServer group %s on SLB %s", vsgID, id)
+		s.own(vsgID)
 		s.alicloudCluster.Status.Network.SLB.VServerGroupId = vsgID
 	}
 
 	// TODO join exist slb, remove hardcode
 	s.alicloudCluster.Status.Message += "-CreateTCPListener"
 	if err := s.slb.CreateTCPListener(spec, id, s.alicloudCluster.Status.Network.SLB.VServerGroupId); err != nil {
+		s.warningf("FailedCreateListener", err, "Failed to create TCP listener on SLB %s", id)
 		return reconcile.Result{}, errors.Wrapf(err, "CreateTCPListener %v", id)
 	}
+	s.eventf("SuccessfulCreateListener", "Created TCP listener on SLB %s", id)
 
 	s.alicloudCluster.Status.Message += "-StartListener"
 	if err := s.slb.StartListener(id); err != nil {
+		s.warningf("FailedStartListener", err, "Failed to start TCP listener on SLB %s", id)
 		return reconcile.Result{}, errors.Wrapf(err, "StartListener %v", id)
 	}
 
-	s.alicloudCluster.Status.ApiEndpoints = []clusterv1.APIEndpoint{
-		{
-			Host: target.Address,
-			Port: 6443,
-		},
-	}
 	_ = s.patch()
 	return reconcile.Result{}, nil
 }
 
 func (s *ClusterProcessor) reconcileSecurityGroup() (reconcile.Result, error) {
-	if len(s.alicloudCluster.Status.Network.SecurityGroup.SecurityGroupId) > 0 {
+	for _, sg := range s.securityGroups() {
+		if rs, err := s.reconcileSecurityGroupOf(sg); err != nil {
+			return rs, errors.Wrapf(err, "reconcileSecurityGroup %v", sg.role)
+		}
+	}
+	return reconcile.Result{}, nil
+}
+
+func (s *ClusterProcessor) reconcileSecurityGroupOf(sg securityGroupScope) (reconcile.Result, error) {
+	if len(sg.status.SecurityGroupId) > 0 {
 		return reconcile.Result{}, nil
 	}
 
-	s.Info("reconcileSecurityGroup")
+	s.Info("reconcileSecurityGroup", "role", sg.role)
 
-	spec := s.alicloudCluster.Spec.Network.SecurityGroup
+	spec := sg.spec
 	id := spec.SecurityGroupId
 
 	var err error
@@ -782,16 +1055,113 @@ func (s *ClusterProcessor) reconcileSecurityGroup() (reconcile.Result, error) {
 	} else {
 		id, err = s.securityGroup.Create(spec, s.alicloudCluster.Status.Network.VPC.VpcId)
 		if err != nil {
+			s.warningf("FailedCreateSecurityGroup", err, "Failed to create %s security group", sg.role)
 			return reconcile.Result{}, errors.Wrap(err, "Create")
 		}
+		s.eventf("SuccessfulCreateSecurityGroup", "Created %s security group %s", sg.role, id)
+		s.own(id)
 		target, err = s.securityGroup.WaitReady(id)
 		if err != nil {
 			return reconcile.Result{}, errors.Wrapf(err, "WaitRead %v", id)
 		}
 	}
 
-	s.Info("reconcileSecurityGroup success", "status", target)
-	target.DeepCopyInto(&s.alicloudCluster.Status.Network.SecurityGroup)
+	s.Info("reconcileSecurityGroup success", "role", sg.role, "status", target)
+	target.DeepCopyInto(sg.status)
 	_ = s.patch()
 	return reconcile.Result{}, nil
 }
+
+func (s *ClusterProcessor) reconcileSecurityGroupRules() (reconcile.Result, error) {
+	for _, sg := range s.securityGroups() {
+		if rs, err := s.reconcileSecurityGroupRulesOf(sg); err != nil {
+			return rs, errors.Wrapf(err, "reconcileSecurityGroupRules %v", sg.role)
+		}
+	}
+	return reconcile.Result{}, nil
+}
+
+// reconcileSecurityGroupRulesOf authorizes the wanted rules that are missing from the security group
+// and revokes the ones the provider authorized earlier but are no longer wanted.
+// Rules added by anyone else are left alone. A managed rule deleted out-of-band is drift: it is
+// authorized again only when Spec.Network.AutoRepair is set and reported through the group's condition otherwise.
+func (s *ClusterProcessor) reconcileSecurityGroupRulesOf(sg securityGroupScope) (reconcile.Result, error) {
+	status := sg.status
+	if len(status.SecurityGroupId) == 0 {
+		return reconcile.Result{}, nil
+	}
+
+	s.Info("reconcileSecurityGroupRules", "role", sg.role)
+
+	current, err := s.securityGroup.DescribeRules(status.SecurityGroupId)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "DescribeRules %v", status.SecurityGroupId)
+	}
+	existing := make(map[string]bool, len(current))
+	for i := range current {
+		existing[current[i].Key()] = true
+	}
+
+	managed := make(map[string]bool, len(status.Rules))
+	for i := range status.Rules {
+		managed[status.Rules[i].Key()] = true
+	}
+
+	// a wanted rule that was already in the group before the provider authorized it stays foreign,
+	// so that dropping it from the spec or deleting an adopted group never revokes it
+	var owned []infrav1.SecurityGroupRuleSpec
+	var missing, repaired []string
+	wanted := map[string]bool{}
+	for i := range sg.rules {
+		rule := &sg.rules[i]
+		if wanted[rule.Key()] {
+			continue
+		}
+		wanted[rule.Key()] = true
+
+		if existing[rule.Key()] {
+			if managed[rule.Key()] {
+				owned = append(owned, *rule)
+			}
+			continue
+		}
+		if managed[rule.Key()] && !s.autoRepair() {
+			missing = append(missing, rule.Key())
+			owned = append(owned, *rule)
+			continue
+		}
+		if err := s.securityGroup.AuthorizeRule(status.SecurityGroupId, rule); err != nil {
+			s.warningf("FailedAuthorizeSecurityGroupRule", err, "Failed to authorize rule %s in security group %s", rule.Key(), status.SecurityGroupId)
+			return reconcile.Result{}, errors.Wrapf(err, "AuthorizeRule %v", rule.Key())
+		}
+		owned = append(owned, *rule)
+		if managed[rule.Key()] {
+			repaired = append(repaired, rule.Key())
+		} else {
+			s.eventf("SuccessfulAuthorizeSecurityGroupRule", "Authorized rule %s in security group %s", rule.Key(), status.SecurityGroupId)
+			// recorded right away so that a later failure in this pass does not turn it foreign
+			managed[rule.Key()] = true
+			status.Rules = append(status.Rules, *rule)
+		}
+	}
+
+	for i := range status.Rules {
+		rule := &status.Rules[i]
+		if wanted[rule.Key()] || !existing[rule.Key()] {
+			continue
+		}
+		if err := s.securityGroup.RevokeRule(status.SecurityGroupId, rule); err != nil {
+			s.warningf("FailedRevokeSecurityGroupRule", err, "Failed to revoke rule %s in security group %s", rule.Key(), status.SecurityGroupId)
+			return reconcile.Result{}, errors.Wrapf(err, "RevokeRule %v", rule.Key())
+		}
+		s.eventf("SuccessfulRevokeSecurityGroupRule", "Revoked rule %s in security group %s", rule.Key(), status.SecurityGroupId)
+	}
+
+	status.Rules = owned
+	if len(missing) > 0 {
+		s.markDrifted(sg.condition, "%s security group %s is missing rules %s", sg.role, status.SecurityGroupId, strings.Join(missing, ", "))
+	} else if len(repaired) > 0 {
+		s.markRepaired(sg.condition, "Authorized rules %s again in %s security group %s", strings.Join(repaired, ", "), sg.role, status.SecurityGroupId)
+	}
+	return reconcile.Result{}, nil
+}
@@ -0,0 +1,148 @@
+package controllers
+
+import (
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-alicloud/api/v1alpha2"
+	"sigs.k8s.io/cluster-api-provider-alicloud/pkg/aliyun"
+)
+
+// reconcileRouteTable resolves the route table that receives the pod CIDR routes of the nodes.
+// A custom table is created or adopted and bound to the cluster VSwitch, otherwise the system table
+// of the VPC router is used. Removing Spec.Network.RouteTable only takes effect when the cluster is deleted.
+func (s *ClusterProcessor) reconcileRouteTable() error {
+	spec := s.alicloudCluster.Spec.Network.RouteTable
+	if spec == nil {
+		return nil
+	}
+
+	s.Info("reconcileRouteTable")
+
+	status := &s.alicloudCluster.Status.Network.RouteTable
+	id := status.RouteTableId
+	if len(id) == 0 {
+		id = spec.RouteTableId
+	}
+
+	if !spec.Custom && len(id) == 0 {
+		target, err := s.routeTable.DescribeSystem(s.alicloudCluster.Status.Network.VPC.VRouterId)
+		if err != nil {
+			return errors.Wrap(err, "DescribeSystem")
+		}
+		if target == nil {
+			s.markDrifted(infrav1.RouteTableReadyCondition, "System route table of VRouter %s not found",
+				s.alicloudCluster.Status.Network.VPC.VRouterId)
+			return errors.Errorf("system route table of %s not found", s.alicloudCluster.Status.Network.VPC.VRouterId)
+		}
+		status.FillFrom(target)
+		s.markAvailable(infrav1.RouteTableReadyCondition)
+		return nil
+	}
+
+	var err error
+	if len(id) > 0 {
+		target, err := s.routeTable.Describe(id)
+		if err != nil {
+			return errors.Wrapf(err, "Describe %v", id)
+		}
+		if target == nil {
+			s.markDrifted(infrav1.RouteTableReadyCondition, "Route table %s no longer exists", id)
+			return errors.Errorf("target not found: %v", id)
+		}
+	} else {
+		id, err = s.routeTable.Create(*spec, s.alicloudCluster.Status.Network.VPC.VpcId)
+		if err != nil {
+			s.warningf("FailedCreateRouteTable", err, "Failed to create route table")
+			return errors.Wrap(err, "Create")
+		}
+		s.eventf("SuccessfulCreateRouteTable", "Created route table %s", id)
+		s.own(id)
+	}
+
+	target, err := s.routeTable.WaitReady(id)
+	if err != nil {
+		return errors.Wrapf(err, "WaitReady %v", id)
+	}
+	status.FillFrom(target)
+	_ = s.patch()
+
+	vswID := s.alicloudCluster.Status.Network.VSwitch.VSwitchId
+	if !contains(target.VSwitchIds.VSwitchId, vswID) {
+		if contains(status.VSwitchIds, vswID) {
+			if !s.autoRepair() {
+				s.markDrifted(infrav1.RouteTableReadyCondition, "VSwitch %s is no longer bound to route table %s", vswID, id)
+				return nil
+			}
+		}
+		if err := s.routeTable.Associate(id, vswID); err != nil {
+			s.warningf("FailedAssociateRouteTable", err, "Failed to bind VSwitch %s to route table %s", vswID, id)
+			return errors.Wrap(err, "Associate")
+		}
+		if contains(status.VSwitchIds, vswID) {
+			s.markRepaired(infrav1.RouteTableReadyCondition, "Bound VSwitch %s to route table %s again", vswID, id)
+			return nil
+		}
+		s.eventf("SuccessfulAssociateRouteTable", "Bound VSwitch %s to route table %s", vswID, id)
+		status.VSwitchIds = append(status.VSwitchIds, vswID)
+	}
+
+	s.Info("reconcileRouteTable success", "status", status)
+	s.markAvailable(infrav1.RouteTableReadyCondition)
+	return nil
+}
+
+// deleteRouteTable unbinds the VSwitches the provider bound, and deletes the custom route table with
+// its remaining routes if the provider created it. Routes of the system table are removed with their machines.
+func (s *ClusterProcessor) deleteRouteTable() error {
+	status := &s.alicloudCluster.Status.Network.RouteTable
+	id := status.RouteTableId
+	if len(id) == 0 || status.RouteTableType == aliyun.RouteTableTypeSystem {
+		return nil
+	}
+
+	s.Info("deleteRouteTable")
+
+	target, err := s.routeTable.Describe(id)
+	if err != nil {
+		return errors.Wrap(err, "Describe")
+	}
+	if target == nil {
+		status.VSwitchIds = nil
+		s.disown(id)
+		return nil
+	}
+
+	if s.owns(id) {
+		entries, err := s.routeTable.DescribeEntries(id, "")
+		if err != nil {
+			return errors.Wrap(err, "DescribeEntries")
+		}
+		for _, e := range entries {
+			if err := s.routeTable.DeleteEntry(id, e.DestinationCidrBlock, e.NextHopId); err != nil {
+				return errors.Wrapf(err, "DeleteEntry %s", e.DestinationCidrBlock)
+			}
+		}
+	}
+
+	for _, vswID := range status.VSwitchIds {
+		if !contains(target.VSwitchIds.VSwitchId, vswID) {
+			continue
+		}
+		if err := s.routeTable.Unassociate(id, vswID); err != nil {
+			s.warningf("FailedUnassociateRouteTable", err, "Failed to unbind VSwitch %s from route table %s", vswID, id)
+			return errors.Wrap(err, "Unassociate")
+		}
+	}
+	status.VSwitchIds = nil
+
+	if !s.owns(id) {
+		s.Info("keep adopted route table", "id", id)
+		return nil
+	}
+	if err := s.routeTable.Delete(id); err != nil {
+		s.warningf("FailedDeleteRouteTable", err, "Failed to delete route table %s", id)
+		return errors.Wrap(err, "Delete")
+	}
+	s.eventf("SuccessfulDeleteRouteTable", "Deleted route table %s", id)
+	s.disown(id)
+	return nil
+}
@@ -0,0 +1,176 @@
+package controllers
+
+import (
+	infrav1 "sigs.k8s.io/cluster-api-provider-alicloud/api/v1alpha2"
+)
+
+const (
+	sharedSecurityGroupRole       = "shared"
+	controlPlaneSecurityGroupRole = "control-plane"
+	nodeSecurityGroupRole         = "node"
+
+	// slbHealthCheckCIDR is where SLB health checks and forwarded traffic originate inside a VPC.
+	slbHealthCheckCIDR = "100.64.0.0/10"
+)
+
+// securityGroupScope ties a security group spec to its status, condition and the rules it should carry.
+type securityGroupScope struct {
+	role      string
+	spec      infrav1.SecurityGroupSpec
+	status    *infrav1.SecurityGroup
+	condition infrav1.ConditionType
+	rules     []infrav1.SecurityGroupRuleSpec
+}
+
+// securityGroups returns the shared security group attached to every machine, followed by
+// the control-plane and node groups carrying the built-in Kubernetes rules for their role,
+// and the bastion group while a bastion is configured or still exists.
+func (s *ClusterProcessor) securityGroups() []securityGroupScope {
+	spec := &s.alicloudCluster.Spec.Network
+	status := &s.alicloudCluster.Status.Network
+
+	controlPlane := spec.ControlPlaneSecurityGroup
+	if len(controlPlane.SecurityGroupName) == 0 {
+		controlPlane.SecurityGroupName = s.cluster.Name + "-controlplane"
+	}
+	node := spec.NodeSecurityGroup
+	if len(node.SecurityGroupName) == 0 {
+		node.SecurityGroupName = s.cluster.Name + "-node"
+	}
+
+	groups := []securityGroupScope{
+		{
+			role:      sharedSecurityGroupRole,
+			spec:      spec.SecurityGroup,
+			status:    &status.SecurityGroup,
+			condition: infrav1.SecurityGroupReadyCondition,
+			rules:     specRules(spec.SecurityGroup.Rules),
+		},
+		{
+			role:      controlPlaneSecurityGroupRole,
+			spec:      controlPlane,
+			status:    &status.ControlPlaneSecurityGroup,
+			condition: infrav1.ControlPlaneSecurityGroupReadyCondition,
+			rules:     append(specRules(controlPlane.Rules), s.controlPlaneRules()...),
+		},
+		{
+			role:      nodeSecurityGroupRole,
+			spec:      node,
+			status:    &status.NodeSecurityGroup,
+			condition: infrav1.NodeSecurityGroupReadyCondition,
+			rules:     append(specRules(node.Rules), s.nodeRules()...),
+		},
+	}
+	if s.bastionEnabled() || len(s.alicloudCluster.Status.Bastion.SecurityGroup.SecurityGroupId) > 0 {
+		groups = append(groups, s.bastionSecurityGroup())
+	}
+	return groups
+}
+
+func specRules(rules []*infrav1.SecurityGroupRuleSpec) []infrav1.SecurityGroupRuleSpec {
+	var list []infrav1.SecurityGroupRuleSpec
+	for _, r := range rules {
+		if r != nil {
+			list = append(list, *r)
+		}
+	}
+	return list
+}
+
+// controlPlaneRules opens the apiserver to the VPC and the SLB, etcd and the kubelet to other
+// control-plane machines, and everything to pod traffic.
+func (s *ClusterProcessor) controlPlaneRules() []infrav1.SecurityGroupRuleSpec {
+	network := &s.alicloudCluster.Status.Network
+	controlPlaneID := network.ControlPlaneSecurityGroup.SecurityGroupId
+
+	var rules []infrav1.SecurityGroupRuleSpec
+	for _, cidr := range s.apiServerSources() {
+		rules = append(rules, cidrRule("tcp", "6443/6443", cidr, "kubernetes apiserver"))
+	}
+	if len(controlPlaneID) > 0 {
+		rules = append(rules,
+			groupRule("tcp", "2379/2380", controlPlaneID, "etcd"),
+			groupRule("tcp", "10250/10250", controlPlaneID, "kubelet"),
+		)
+	}
+	rules = append(rules, s.bastionRules()...)
+	return append(rules, s.podRules()...)
+}
+
+// nodeRules opens the kubelet to the control plane, the NodePort range to the VPC and the SLB,
+// and everything to pod traffic.
+func (s *ClusterProcessor) nodeRules() []infrav1.SecurityGroupRuleSpec {
+	network := &s.alicloudCluster.Status.Network
+	controlPlaneID := network.ControlPlaneSecurityGroup.SecurityGroupId
+
+	var rules []infrav1.SecurityGroupRuleSpec
+	if len(controlPlaneID) > 0 {
+		rules = append(rules, groupRule("tcp", "10250/10250", controlPlaneID, "kubelet"))
+	}
+	for _, cidr := range []string{network.VPC.CidrBlock, slbHealthCheckCIDR} {
+		if len(cidr) == 0 {
+			continue
+		}
+		rules = append(rules,
+			cidrRule("tcp", "30000/32767", cidr, "kubernetes nodeport"),
+			cidrRule("udp", "30000/32767", cidr, "kubernetes nodeport"),
+		)
+	}
+	rules = append(rules, s.bastionRules()...)
+	return append(rules, s.podRules()...)
+}
+
+// apiServerSources lists where apiserver clients connect from. SLB TCP listeners keep the client
+// address, so an internet-facing SLB or external access to a private one means the apiserver
+// must accept any source.
+func (s *ClusterProcessor) apiServerSources() []string {
+	network := &s.alicloudCluster.Status.Network
+
+	var sources []string
+	if len(network.VPC.CidrBlock) > 0 {
+		sources = append(sources, network.VPC.CidrBlock)
+	}
+	sources = append(sources, slbHealthCheckCIDR)
+	switch {
+	case network.SLB.AddressType == "internet", len(s.externalAccess()) > 0:
+		sources = append(sources, "0.0.0.0/0")
+	case len(network.SLB.Address) > 0:
+		sources = append(sources, network.SLB.Address+"/32")
+	}
+	return sources
+}
+
+func (s *ClusterProcessor) podRules() []infrav1.SecurityGroupRuleSpec {
+	clusterNetwork := s.cluster.Spec.ClusterNetwork
+	if clusterNetwork == nil || clusterNetwork.Pods == nil {
+		return nil
+	}
+
+	var rules []infrav1.SecurityGroupRuleSpec
+	for _, cidr := range clusterNetwork.Pods.CIDRBlocks {
+		rules = append(rules, cidrRule("all", "-1/-1", cidr, "kubernetes pods"))
+	}
+	return rules
+}
+
+func cidrRule(protocol, portRange, cidr, description string) infrav1.SecurityGroupRuleSpec {
+	return infrav1.SecurityGroupRuleSpec{
+		Direction:    infrav1.RuleDirectionIngress,
+		NicType:      "intranet",
+		IpProtocol:   protocol,
+		PortRange:    portRange,
+		SourceCidrIp: cidr,
+		Description:  description,
+	}
+}
+
+func groupRule(protocol, portRange, groupID, description string) infrav1.SecurityGroupRuleSpec {
+	return infrav1.SecurityGroupRuleSpec{
+		Direction:     infrav1.RuleDirectionIngress,
+		NicType:       "intranet",
+		IpProtocol:    protocol,
+		PortRange:     portRange,
+		SourceGroupId: groupID,
+		Description:   description,
+	}
+}
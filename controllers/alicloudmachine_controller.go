@@ -17,15 +17,20 @@ package controllers
 
 import (
 	rawctx "context"
+	"time"
+
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha2"
 	"sigs.k8s.io/cluster-api/util"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-alicloud/api/v1alpha2"
@@ -34,12 +39,26 @@ import (
 // AlicloudMachineReconciler reconciles a AlicloudMachine object
 type AlicloudMachineReconciler struct {
 	client.Client
-	Log    logr.Logger
-	Scheme *runtime.Scheme
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// ResyncPeriod is how often a ready machine is compared against its live ECS instance.
+	// Zero disables periodic resync.
+	ResyncPeriod time.Duration
+
+	// MaxConcurrentReconciles is the number of AlicloudMachines reconciled in parallel. Defaults to 1.
+	MaxConcurrentReconciles int
+
+	// ClusterSelector restricts the reconciler to the machines of the clusters whose CAPI Cluster labels
+	// match. Nil selects every cluster.
+	ClusterSelector labels.Selector
 }
 
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=alicloudmachines,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=alicloudmachines/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;patch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
 
 func (r *AlicloudMachineReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	ctx := rawctx.Background()
@@ -70,6 +89,10 @@ func (r *AlicloudMachineReconciler) Reconcile(req ctrl.Request) (ctrl.Result, er
 		_logger.Info("cluster not found")
 		return reconcile.Result{}, nil
 	}
+	if !selectsCluster(r.ClusterSelector, cluster) {
+		_logger.V(1).Info("cluster not selected by this instance", "cluster", cluster.Name)
+		return reconcile.Result{}, nil
+	}
 
 	if err := r.Client.Get(ctx, client.ObjectKey{
 		Namespace: cluster.Namespace,
@@ -106,5 +129,47 @@ func (r *AlicloudMachineReconciler) SetupWithManager(mgr ctrl.Manager) error {
 				ToRequests: util.MachineToInfrastructureMapFunc(infrav1.GroupVersion.WithKind("AlicloudMachine")),
 			},
 		).
+		Watches(
+			&source.Kind{Type: &infrav1.AlicloudCluster{}},
+			&handler.EnqueueRequestsFromMapFunc{
+				ToRequests: handler.ToRequestsFunc(r.alicloudClusterToAlicloudMachines),
+			},
+		).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
 		Complete(r)
 }
+
+// alicloudClusterToAlicloudMachines maps an AlicloudCluster to the AlicloudMachines of its cluster, so that
+// machines waiting for the cluster infrastructure are reconciled as soon as it becomes ready.
+func (r *AlicloudMachineReconciler) alicloudClusterToAlicloudMachines(o handler.MapObject) []reconcile.Request {
+	ctx := rawctx.Background()
+	clusterInfra, ok := o.Object.(*infrav1.AlicloudCluster)
+	if !ok {
+		return nil
+	}
+	logger := r.Log.WithValues("AlicloudCluster", o.Meta.GetNamespace()+"/"+o.Meta.GetName())
+
+	cluster, err := util.GetOwnerCluster(ctx, r.Client, clusterInfra.ObjectMeta)
+	if err != nil {
+		logger.Error(err, "GetOwnerCluster error")
+		return nil
+	}
+	if cluster == nil {
+		return nil
+	}
+
+	machines := &clusterv1.MachineList{}
+	if err := r.List(ctx, machines, client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{clusterv1.MachineClusterLabelName: cluster.Name}); err != nil {
+		logger.Error(err, "List Machines error")
+		return nil
+	}
+
+	mapFunc := util.MachineToInfrastructureMapFunc(infrav1.GroupVersion.WithKind("AlicloudMachine"))
+	var requests []reconcile.Request
+	for i := range machines.Items {
+		m := &machines.Items[i]
+		requests = append(requests, mapFunc(handler.MapObject{Meta: m, Object: m})...)
+	}
+	return requests
+}
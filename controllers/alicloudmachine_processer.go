@@ -14,9 +14,15 @@ import (
 	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
 	"github.com/juju/errors"
+	"golang.org/x/time/rate"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	infrav1 "sigs.k8s.io/cluster-api-provider-alicloud/api/v1alpha2"
 	"sigs.k8s.io/cluster-api-provider-alicloud/pkg/aliyun"
+	"sigs.k8s.io/cluster-api-provider-alicloud/pkg/aliyun/metrics"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha2"
+	"sigs.k8s.io/cluster-api/controllers/remote"
+	capierrors "sigs.k8s.io/cluster-api/errors"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/patch"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -36,7 +42,12 @@ type MachineProcesser struct {
 	result       ctrl.Result
 	err          error
 	ecsEnginer   *ecs.Client
+	ecsLimiter   *rate.Limiter
+	instances    *aliyun.InstanceCache
 	slbEnginer   *aliyun.SLBClient
+	sgEnginer    *aliyun.SecurityGroupClient
+	eniEnginer   *aliyun.NetworkInterfaceClient
+	routeEnginer *aliyun.RouteTableClient
 	ecsInstance  *ecs.Instance
 	isChange     bool
 	pather       *patch.Helper
@@ -52,6 +63,14 @@ func (p *MachineProcesser) init() {
 		return
 	}
 	p.ecsEnginer = ecsClient
+	p.ecsLimiter = aliyun.Limiter(p.Info().RegionId(), "ecs")
+
+	instances, err := aliyun.InstanceCacheFor(p.Info().RegionId())
+	if err != nil {
+		p.err = err
+		return
+	}
+	p.instances = instances
 
 	slbClient, err := aliyun.NewSLBClient(p.Log, p.Info().RegionId())
 	if err != nil {
@@ -60,6 +79,27 @@ func (p *MachineProcesser) init() {
 	}
 	p.slbEnginer = slbClient
 
+	sgClient, err := aliyun.NewSecurityGroupClient(p.Log, p.Info().RegionId())
+	if err != nil {
+		p.err = err
+		return
+	}
+	p.sgEnginer = sgClient
+
+	eniClient, err := aliyun.NewNetworkInterfaceClient(p.Log, p.Info().RegionId())
+	if err != nil {
+		p.err = err
+		return
+	}
+	p.eniEnginer = eniClient
+
+	routeClient, err := aliyun.NewRouteTableClient(p.Log, p.Info().RegionId())
+	if err != nil {
+		p.err = err
+		return
+	}
+	p.routeEnginer = routeClient
+
 	if patcher, err := patch.NewHelper(p.machineInfra, p.Client); err == nil {
 		p.pather = patcher
 	} else {
@@ -67,6 +107,14 @@ func (p *MachineProcesser) init() {
 	}
 }
 
+func (p *MachineProcesser) eventf(reason, messageFmt string, args ...interface{}) {
+	p.Recorder.Eventf(p.machineInfra, corev1.EventTypeNormal, reason, messageFmt, args...)
+}
+
+func (p *MachineProcesser) warningf(reason string, err error, messageFmt string, args ...interface{}) {
+	recordWarning(p.Recorder, p.machineInfra, reason, err, messageFmt, args...)
+}
+
 func (p *MachineProcesser) gobreak(err error) {
 	p.err = err
 	return
@@ -87,18 +135,13 @@ func (p *MachineProcesser) goRetry(dur time.Duration) {
 func (p *MachineProcesser) tryGetInstance() error {
 	info := p.Info()
 	if len(info.id()) > 0 {
-		req := ecs.CreateDescribeInstancesRequest()
-		req.RegionId = info.RegionId()
-		req.InstanceIds = fmt.Sprintf(`["%s"]`, info.id())
-		p.Log.Info("DescribeInstances Request", "request", req)
-		response, err := p.ecsEnginer.DescribeInstances(req)
+		instance, err := p.instances.Get(info.id())
 		if err != nil {
 			return err
 		}
-		p.Log.Info("Get Instance", "resp", response)
-		if len(response.Instances.Instance) > 0 {
-			p.Log.Info("recive EcsInstance: id=" + response.Instances.Instance[0].InstanceId)
-			p.ecsInstance = &response.Instances.Instance[0]
+		if instance != nil {
+			p.Log.Info("recive EcsInstance: id=" + instance.InstanceId)
+			p.ecsInstance = instance
 		}
 
 	}
@@ -110,26 +153,37 @@ func (p *MachineProcesser) setProviderID(v string) {
 	p.isChange = true
 }
 
-type instanceCreateOption func(*ecs.RunInstancesRequest)
+type instanceCreateOption func(*ecs.RunInstancesRequest) error
 
 func (p *MachineProcesser) createInstance(opts ...instanceCreateOption) error {
 
 	req := ecs.CreateRunInstancesRequest()
 
 	for _, f := range opts {
-		f(req)
+		if err := f(req); err != nil {
+			p.warningf("FailedCreateInstance", err, "Failed to prepare ECS instance request")
+			return err
+		}
 	}
 	p.Log.Info("create instance ", "request", req)
 
-	reponse, err := p.ecsEnginer.RunInstances(req)
+	var reponse *ecs.RunInstancesResponse
+	err := aliyun.Call(p.ecsLimiter, "ecs", "RunInstances", func() error {
+		var err error
+		reponse, err = p.ecsEnginer.RunInstances(req)
+		return err
+	})
 	if err != nil {
 		p.Log.Error(err, "create ecs instance")
+		p.warningf("FailedCreateInstance", err, "Failed to launch ECS instance")
 		return err
 	}
 	if len(reponse.InstanceIdSets.InstanceIdSet) > 0 {
 		id := reponse.InstanceIdSets.InstanceIdSet[0]
 		p.Log.Info("set id ", "id", id)
 		p.Info().setId(id)
+		p.instances.Invalidate(id)
+		p.eventf("SuccessfulCreateInstance", "Launched ECS instance %s", id)
 	}
 	return nil
 }
@@ -139,11 +193,18 @@ func (p *MachineProcesser) deleteInstance() error {
 	req.Force = requests.NewBoolean(true)
 	req.InstanceId = p.Info().id()
 	req.RegionId = p.Info().RegionId()
-	if _, err := p.ecsEnginer.DeleteInstance(req); err != nil {
+	err := aliyun.Call(p.ecsLimiter, "ecs", "DeleteInstance", func() error {
+		_, err := p.ecsEnginer.DeleteInstance(req)
+		return err
+	})
+	if err != nil {
 		p.Log.Error(err, "delete instance error", "InstanceId", req.InstanceId)
+		p.warningf("FailedDeleteInstance", err, "Failed to delete ECS instance %s", req.InstanceId)
 		return err
 	}
+	p.instances.Invalidate(req.InstanceId)
 	p.Log.Info("delete instance ok", "InstanceId", req.InstanceId)
+	p.eventf("SuccessfulDeleteInstance", "Deleted ECS instance %s", req.InstanceId)
 	return nil
 }
 
@@ -161,14 +222,24 @@ func (p *MachineProcesser) unbindFinalizers() {
 	}
 }
 
+// reconcileSLBEndpoint registers the control-plane instance with the apiserver SLB and with the
+// external SLB of a private cluster and the IPv6 SLB of a dual-stack cluster when they exist.
 func (p *MachineProcesser) reconcileSLBEndpoint() error {
-	lbID := p.clusterInfra.Status.Network.SLB.VServerGroupId
-	_, err := p.slbEnginer.VGAddBackendServers(lbID, p.Info().id(), "6443", p.ecsInstance.HostName)
-	if err != nil {
-		return err
+	network := p.clusterInfra.Status.Network
+	for _, lb := range []infrav1.SLB{network.SLB, network.ExternalSLB, network.IPv6SLB} {
+		if len(lb.VServerGroupId) == 0 {
+			continue
+		}
+		if _, err := p.slbEnginer.VGAddBackendServers(lb.VServerGroupId, p.Info().id(), "6443", p.ecsInstance.HostName); err != nil {
+			p.warningf("FailedRegisterBackend", err, "Failed to register instance %s to VServer group %s", p.Info().id(), lb.VServerGroupId)
+			return err
+		}
+		p.eventf("SuccessfulRegisterBackend", "Registered instance %s to VServer group %s", p.Info().id(), lb.VServerGroupId)
+		if err := p.slbEnginer.StartListener(lb.LoadBalancerId); err != nil {
+			return err
+		}
 	}
-	slbID := p.clusterInfra.Status.Network.SLB.LoadBalancerId
-	return p.slbEnginer.StartListener(slbID)
+	return nil
 }
 
 func (p *MachineProcesser) commit() {
@@ -191,6 +262,18 @@ func (p *MachineProcesser) handleDelete() {
 		}
 	}()
 
+	if route := p.machineInfra.Status.PodRoute; route != nil {
+		if err := p.routeEnginer.DeleteEntry(route.RouteTableId, route.DestinationCidrBlock, route.NextHopId); err != nil {
+			p.warningf("FailedDeletePodRoute", err, "Failed to delete route %s", route.DestinationCidrBlock)
+			p.goRetry(time.Second * 20)
+			return
+		}
+		p.eventf("SuccessfulDeletePodRoute", "Deleted route %s to instance %s", route.DestinationCidrBlock, route.NextHopId)
+		p.Info().updateMachineStatus(func(status *infrav1.AlicloudMachineStatus) {
+			status.PodRoute = nil
+		})
+	}
+
 	if p.Info().id() != "" {
 		if err := p.tryGetInstance(); err != nil {
 			p.Log.Error(err, "get ecs instance error when handle delete")
@@ -257,10 +340,20 @@ func (p *MachineProcesser) sync() {
 	if info.id() == "" {
 
 		p.Log.Info("id is null, so create instance")
-		if err := p.createInstance(func(req *ecs.RunInstancesRequest) {
+		extraGroups, err := p.resolveAdditionalSecurityGroups()
+		if err != nil {
+			p.Log.Error(err, "resolveAdditionalSecurityGroups")
+			p.warningf("FailedResolveSecurityGroups", err, "Failed to resolve additional security groups")
+			p.goRetry(time.Second * 30)
+			return
+		}
+		if err := p.createInstance(func(req *ecs.RunInstancesRequest) error {
 			if err := info.FillRunInstancesReq(req); err != nil {
 				p.Log.Error(err, "Fill RunInstances Request")
+				return err
 			}
+			appendSecurityGroups(req, extraGroups)
+			return nil
 		}); err != nil {
 			p.Log.Error(err, "create ecs instance")
 			p.goRetry(time.Second * 30)
@@ -274,6 +367,12 @@ func (p *MachineProcesser) sync() {
 		return
 	}
 
+	// an instance that was observed before and is now missing was released out-of-band
+	if p.ecsInstance == nil && p.machineInfra.Status.Instance != nil {
+		p.markInstanceGone()
+		return
+	}
+
 	// ecs get instance after create is null
 	if p.ecsInstance == nil {
 		p.Log.Info("p.ecsInstance is nil go retry...")
@@ -282,11 +381,35 @@ func (p *MachineProcesser) sync() {
 	}
 
 	info.updateMachineStatus(func(status *infrav1.AlicloudMachineStatus) {
+		wasReady := status.Ready
 
 		status.Addresses = info.getAddresses()
+		if p.clusterInfra.Spec.Network.DualStack {
+			ipv6, err := p.eniEnginer.DescribeIpv6Addresses(p.ecsInstance.InstanceId)
+			if err != nil {
+				p.Log.Error(err, "DescribeIpv6Addresses")
+				p.goRetry(time.Second * 15)
+			}
+			for _, address := range ipv6 {
+				status.Addresses = append(status.Addresses, clusterv1.MachineAddress{
+					Type:    clusterv1.MachineInternalIP,
+					Address: address,
+				})
+			}
+		}
 		status.Instance = info.instance()
+		now := metav1.Now()
+		status.LastSyncTime = &now
 
 		p.setProviderID(status.Instance.InstanceId)
+		p.detectDrift(status)
+
+		if err := p.reconcileNetworkInterfaces(status); err != nil {
+			p.Log.Error(err, "reconcileNetworkInterfaces")
+			p.goRetry(time.Second * 15)
+		}
+
+		status.Ready = false
 		if len(status.Addresses) > 0 && status.Instance.Status == "Running" {
 			status.Ready = true
 
@@ -300,13 +423,241 @@ func (p *MachineProcesser) sync() {
 			}
 		}
 
-		if !status.Ready {
+		switch {
+		case status.Ready:
+			if !wasReady {
+				metrics.ObserveMachineReady(time.Since(p.machineInfra.CreationTimestamp.Time))
+			}
+			if err := p.reconcilePodRoute(status); err != nil {
+				p.Log.Info("reconcilePodRoute: " + err.Error())
+				p.goRetry(time.Second * 15)
+			}
+			p.resync()
+		case isStopped(status.Instance.Status):
+			p.resync()
+		default:
 			p.goRetry(time.Second * 15)
 		}
 	})
 
 }
 
+// resolveAdditionalSecurityGroups returns the IDs of the security groups listed in the spec,
+// looking up tag selectors in the cluster VPC.
+func (p *MachineProcesser) resolveAdditionalSecurityGroups() ([]string, error) {
+	var ids []string
+	for _, ref := range p.machineInfra.Spec.AdditionalSecurityGroups {
+		if len(ref.ID) > 0 {
+			ids = append(ids, ref.ID)
+			continue
+		}
+		if len(ref.Tags) == 0 {
+			continue
+		}
+		found, err := p.sgEnginer.DescribeByTags(p.clusterInfra.Status.Network.VPC.VpcId, ref.Tags)
+		if err != nil {
+			return nil, err
+		}
+		if len(found) == 0 {
+			return nil, errors.Errorf("no security group in VPC %s matches tags %v",
+				p.clusterInfra.Status.Network.VPC.VpcId, ref.Tags)
+		}
+		ids = append(ids, found...)
+	}
+	return ids, nil
+}
+
+// appendSecurityGroups adds extra security groups to the primary network interface, skipping duplicates.
+func appendSecurityGroups(req *ecs.RunInstancesRequest, extra []string) {
+	if len(extra) == 0 {
+		return
+	}
+	var ids []string
+	if len(req.SecurityGroupId) > 0 {
+		ids = append(ids, req.SecurityGroupId)
+	}
+	if req.SecurityGroupIds != nil {
+		ids = append(ids, *req.SecurityGroupIds...)
+	}
+	for _, id := range extra {
+		if !util.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	req.SecurityGroupId = ""
+	req.SecurityGroupIds = &ids
+}
+
+// reconcileNetworkInterfaces records the secondary network interfaces created with the instance and
+// assigns their secondary private IPs once.
+func (p *MachineProcesser) reconcileNetworkInterfaces(status *infrav1.AlicloudMachineStatus) error {
+	specs := p.machineInfra.Spec.NetworkInterfaces
+	if len(specs) == 0 {
+		return nil
+	}
+
+	enis, err := p.eniEnginer.DescribeByInstance(p.ecsInstance.InstanceId)
+	if err != nil {
+		return errors.Annotate(err, "DescribeByInstance")
+	}
+	byName := make(map[string]ecs.NetworkInterfaceSet, len(enis))
+	for _, eni := range enis {
+		byName[eni.NetworkInterfaceName] = eni
+	}
+
+	observed := make([]infrav1.NetworkInterfaceStatus, 0, len(specs))
+	for i, spec := range specs {
+		name := networkInterfaceName(p.Info().MachineName(), i)
+		eni, ok := byName[name]
+		if !ok {
+			return errors.Errorf("network interface %s is not attached to instance %s yet", name, p.ecsInstance.InstanceId)
+		}
+
+		eniStatus := infrav1.NetworkInterfaceStatus{
+			ID:               eni.NetworkInterfaceId,
+			Name:             name,
+			VSwitchId:        eni.VSwitchId,
+			PrimaryIpAddress: eni.PrivateIpAddress,
+		}
+		for _, old := range status.NetworkInterfaces {
+			if old.ID == eni.NetworkInterfaceId {
+				eniStatus.SecondaryIPsAssigned = old.SecondaryIPsAssigned
+			}
+		}
+		wantIPs := len(spec.SecondaryPrivateIpAddresses) > 0 || spec.SecondaryPrivateIpAddressCount > 0
+		if wantIPs && !eniStatus.SecondaryIPsAssigned {
+			err := p.eniEnginer.AssignPrivateIpAddresses(eni.NetworkInterfaceId,
+				spec.SecondaryPrivateIpAddresses, spec.SecondaryPrivateIpAddressCount)
+			if err != nil {
+				p.warningf("FailedAssignPrivateIps", err, "Failed to assign secondary private IPs to network interface %s", eni.NetworkInterfaceId)
+				status.NetworkInterfaces = observed
+				return errors.Annotate(err, "AssignPrivateIpAddresses")
+			}
+			p.eventf("SuccessfulAssignPrivateIps", "Assigned secondary private IPs to network interface %s", eni.NetworkInterfaceId)
+			eniStatus.SecondaryIPsAssigned = true
+		}
+		observed = append(observed, eniStatus)
+	}
+	status.NetworkInterfaces = observed
+	return nil
+}
+
+// reconcilePodRoute routes the pod CIDR of the workload cluster node to this instance
+// when the cluster manages a route table.
+func (p *MachineProcesser) reconcilePodRoute(status *infrav1.AlicloudMachineStatus) error {
+	tableID := p.clusterInfra.Status.Network.RouteTable.RouteTableId
+	if len(tableID) == 0 {
+		return nil
+	}
+	if p.machine.Status.NodeRef == nil {
+		return errors.New("waiting for the node to join the cluster")
+	}
+
+	remoteClient, err := remote.NewClusterClient(p.Client, p.cluster)
+	if err != nil {
+		return errors.Annotate(err, "NewClusterClient")
+	}
+	coreClient, err := remoteClient.CoreV1()
+	if err != nil {
+		return errors.Annotate(err, "CoreV1")
+	}
+	node, err := coreClient.Nodes().Get(p.machine.Status.NodeRef.Name, metav1.GetOptions{})
+	if err != nil {
+		return errors.Annotatef(err, "get node %s", p.machine.Status.NodeRef.Name)
+	}
+	cidr := node.Spec.PodCIDR
+	if len(cidr) == 0 {
+		return errors.Errorf("node %s has no pod CIDR yet", node.Name)
+	}
+
+	instanceID := p.ecsInstance.InstanceId
+	if old := status.PodRoute; old != nil {
+		if old.RouteTableId == tableID && old.DestinationCidrBlock == cidr && old.NextHopId == instanceID {
+			return nil
+		}
+		if err := p.routeEnginer.DeleteEntry(old.RouteTableId, old.DestinationCidrBlock, old.NextHopId); err != nil {
+			return errors.Annotate(err, "DeleteEntry")
+		}
+		status.PodRoute = nil
+	}
+
+	if err := p.routeEnginer.CreateEntry(tableID, cidr, instanceID, p.Info().MachineName()); err != nil {
+		p.warningf("FailedCreatePodRoute", err, "Failed to create route %s to instance %s", cidr, instanceID)
+		return errors.Annotate(err, "CreateEntry")
+	}
+	p.eventf("SuccessfulCreatePodRoute", "Created route %s to instance %s", cidr, instanceID)
+	status.PodRoute = &infrav1.RouteEntry{
+		RouteTableId:         tableID,
+		DestinationCidrBlock: cidr,
+		NextHopId:            instanceID,
+	}
+	return nil
+}
+
+func networkInterfaceName(machineName string, i int) string {
+	return fmt.Sprintf("%s-eni-%d", machineName, i+1)
+}
+
+// resync requeues the machine so the live instance is checked again after ResyncPeriod.
+func (p *MachineProcesser) resync() {
+	if p.ResyncPeriod > 0 {
+		p.goRetry(p.ResyncPeriod)
+	}
+}
+
+func isStopped(status string) bool {
+	return status == "Stopping" || status == "Stopped"
+}
+
+// detectDrift updates the instance conditions from the live instance and reports out-of-band changes.
+func (p *MachineProcesser) detectDrift(status *infrav1.AlicloudMachineStatus) {
+	instance := status.Instance
+	wasRunning := status.Conditions.IsTrue(infrav1.InstanceReadyCondition)
+	switch {
+	case instance.Status == "Running":
+		status.Conditions.MarkTrue(infrav1.InstanceReadyCondition, infrav1.InstanceRunningReason, "")
+	case isStopped(instance.Status):
+		msg := fmt.Sprintf("ECS instance %s is %s", instance.InstanceId, instance.Status)
+		status.Conditions.MarkFalse(infrav1.InstanceReadyCondition, infrav1.InstanceStoppedReason, msg)
+		if wasRunning {
+			p.Log.Info("instance stopped out-of-band", "InstanceId", instance.InstanceId, "status", instance.Status)
+			p.Recorder.Event(p.machineInfra, corev1.EventTypeWarning, infrav1.InstanceStoppedReason, msg)
+		}
+	default:
+		status.Conditions.MarkFalse(infrav1.InstanceReadyCondition, infrav1.InstancePendingReason,
+			fmt.Sprintf("ECS instance %s is %s", instance.InstanceId, instance.Status))
+	}
+
+	wanted := p.machineInfra.Spec.InstanceType
+	if len(wanted) > 0 && instance.InstanceType != wanted {
+		msg := fmt.Sprintf("ECS instance %s has type %s, want %s", instance.InstanceId, instance.InstanceType, wanted)
+		if status.Conditions.IsTrue(infrav1.InstanceSpecSyncedCondition) {
+			p.Log.Info("instance resized out-of-band", "InstanceId", instance.InstanceId, "InstanceType", instance.InstanceType)
+			p.Recorder.Event(p.machineInfra, corev1.EventTypeWarning, infrav1.InstanceTypeChangedReason, msg)
+		}
+		status.Conditions.MarkFalse(infrav1.InstanceSpecSyncedCondition, infrav1.InstanceTypeChangedReason, msg)
+		return
+	}
+	status.Conditions.MarkTrue(infrav1.InstanceSpecSyncedCondition, infrav1.InstanceSpecMatchingReason, "")
+}
+
+// markInstanceGone fails the machine after its instance was released outside of the provider.
+func (p *MachineProcesser) markInstanceGone() {
+	id := p.machineInfra.Status.Instance.InstanceId
+	msg := fmt.Sprintf("ECS instance %s no longer exists", id)
+	p.Log.Info("instance released out-of-band", "InstanceId", id)
+	p.Recorder.Event(p.machineInfra, corev1.EventTypeWarning, infrav1.InstanceNotFoundReason, msg)
+
+	p.Info().updateMachineStatus(func(status *infrav1.AlicloudMachineStatus) {
+		now := metav1.Now()
+		status.LastSyncTime = &now
+		status.Ready = false
+		status.ErrorReason = string(capierrors.UpdateMachineError)
+		status.ErrorMessage = msg
+		status.Conditions.MarkFalse(infrav1.InstanceReadyCondition, infrav1.InstanceNotFoundReason, msg)
+	})
+}
+
 func (p *MachineProcesser) Info() *InfoProvider {
 	return &InfoProvider{
 		store: p,
@@ -333,8 +684,43 @@ func (s *InfoProvider) MachineName() string {
 	return s.store.machine.Name
 }
 
-func (s *InfoProvider) SecurityGroupId() string {
-	return s.store.clusterInfra.Status.Network.SecurityGroup.SecurityGroupId
+// SecurityGroupIds returns the security group of the machine's role followed by the shared one.
+// Clusters created before role groups existed only have the shared group.
+func (s *InfoProvider) SecurityGroupIds() []string {
+	network := s.store.clusterInfra.Status.Network
+	role := network.NodeSecurityGroup.SecurityGroupId
+	if s.IsControlPlane() {
+		role = network.ControlPlaneSecurityGroup.SecurityGroupId
+	}
+
+	var ids []string
+	for _, id := range []string{role, network.SecurityGroup.SecurityGroupId} {
+		if len(id) > 0 {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func (s *InfoProvider) networkInterfaces() *[]ecs.RunInstancesNetworkInterface {
+	var enis []ecs.RunInstancesNetworkInterface
+	for i, spec := range s.store.machineInfra.Spec.NetworkInterfaces {
+		eni := ecs.RunInstancesNetworkInterface{
+			NetworkInterfaceName: networkInterfaceName(s.MachineName(), i),
+			VSwitchId:            spec.VSwitchId,
+			SecurityGroupId:      spec.SecurityGroupId,
+			PrimaryIpAddress:     spec.PrimaryIpAddress,
+			Description:          spec.Description,
+		}
+		if len(eni.VSwitchId) == 0 {
+			eni.VSwitchId = s.VSwitchId()
+		}
+		if ids := s.SecurityGroupIds(); len(eni.SecurityGroupId) == 0 && len(ids) > 0 {
+			eni.SecurityGroupId = ids[0]
+		}
+		enis = append(enis, eni)
+	}
+	return &enis
 }
 
 func (s *InfoProvider) IsMachineReady() bool {
@@ -404,7 +790,20 @@ func (s *InfoProvider) FillRunInstancesReq(req *ecs.RunInstancesRequest) error {
 	req.ZoneId = s.ZoneId()
 	req.VSwitchId = s.VSwitchId()
 	req.InstanceName = s.MachineName()
-	req.SecurityGroupId = s.SecurityGroupId()
+	switch ids := s.SecurityGroupIds(); len(ids) {
+	case 0:
+		return errors.New("no security group available for machine")
+	case 1:
+		req.SecurityGroupId = ids[0]
+	default:
+		req.SecurityGroupIds = &ids
+	}
+	if len(s.store.machineInfra.Spec.NetworkInterfaces) > 0 {
+		req.NetworkInterface = s.networkInterfaces()
+	}
+	if s.store.clusterInfra.Spec.Network.DualStack {
+		req.Ipv6AddressCount = requests.NewInteger(1)
+	}
 	req.MinAmount = requests.NewInteger(1)
 	req.Amount = requests.NewInteger(1)
 	//if s.IsControlPlane() {
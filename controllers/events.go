@@ -0,0 +1,24 @@
+package controllers
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	"sigs.k8s.io/cluster-api-provider-alicloud/pkg/aliyun"
+)
+
+// recordWarning emits a Warning event for a failed cloud action, appending the
+// Alibaba Cloud error code and request ID so they show up in `kubectl describe`.
+func recordWarning(recorder record.EventRecorder, obj runtime.Object, reason string, err error, messageFmt string, args ...interface{}) {
+	message := fmt.Sprintf(messageFmt, args...)
+
+	code, requestID, sdkMessage := aliyun.ErrorDetail(err)
+	if len(code) == 0 {
+		recorder.Eventf(obj, corev1.EventTypeWarning, reason, "%s: %v", message, err)
+		return
+	}
+	recorder.Eventf(obj, corev1.EventTypeWarning, reason, "%s: %s (ErrorCode: %s, RequestId: %s)", message, sdkMessage, code, requestID)
+}
@@ -18,14 +18,21 @@ package main
 import (
 	"flag"
 	"os"
+	"strings"
+	"time"
 
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	infrav1 "sigs.k8s.io/cluster-api-provider-alicloud/api/v1alpha2"
 	"sigs.k8s.io/cluster-api-provider-alicloud/controllers"
+	"sigs.k8s.io/cluster-api-provider-alicloud/pkg/aliyun"
+	"sigs.k8s.io/cluster-api-provider-alicloud/pkg/aliyun/metrics"
+	"sigs.k8s.io/cluster-api-provider-alicloud/pkg/healthz"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha2"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	// +kubebuilder:scaffold:imports
 )
@@ -47,38 +54,136 @@ func init() {
 func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
+	var machineResyncPeriod time.Duration
+	var clusterResyncPeriod time.Duration
+	var clusterConcurrency int
+	var machineConcurrency int
+	var syncPeriod time.Duration
+	var watchNamespace string
+	var webhookPort int
+	var webhookCertDir string
+	var healthAddr string
+	var clusterSelector string
+	var probeRegion string
+	var probeInterval time.Duration
 	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. Enabling this will ensure there is only one active controller manager.")
+	flag.Float64Var(&aliyun.RateLimitQPS, "cloud-api-qps", aliyun.RateLimitQPS,
+		"Maximum sustained Alibaba Cloud API calls per second, shared per account, region and service.")
+	flag.IntVar(&aliyun.RateLimitBurst, "cloud-api-burst", aliyun.RateLimitBurst,
+		"Maximum burst of Alibaba Cloud API calls above --cloud-api-qps.")
+	flag.DurationVar(&aliyun.InstanceCacheTTL, "instance-cache-ttl", aliyun.InstanceCacheTTL,
+		"How long a described ECS instance is shared between reconciles before it is described again.")
+	flag.DurationVar(&machineResyncPeriod, "machine-resync-period", 10*time.Minute,
+		"How often ready AlicloudMachines are compared against their live ECS instances. 0 disables periodic resync.")
+	flag.DurationVar(&clusterResyncPeriod, "cluster-resync-period", 10*time.Minute,
+		"How often the network of ready AlicloudClusters is verified against the cloud. 0 disables periodic resync.")
+	flag.IntVar(&clusterConcurrency, "alicloudcluster-concurrency", 1,
+		"Number of AlicloudClusters reconciled in parallel.")
+	flag.IntVar(&machineConcurrency, "alicloudmachine-concurrency", 1,
+		"Number of AlicloudMachines reconciled in parallel.")
+	flag.DurationVar(&syncPeriod, "sync-period", 10*time.Hour,
+		"Minimum interval at which every watched object is reconciled again.")
+	flag.StringVar(&watchNamespace, "namespace", "",
+		"Comma separated namespaces the manager watches. Empty watches all namespaces.")
+	flag.IntVar(&webhookPort, "webhook-port", 9443, "The port the webhook server binds to.")
+	flag.StringVar(&webhookCertDir, "webhook-cert-dir", "",
+		"The directory holding tls.crt and tls.key of the webhook server. Empty uses the controller-runtime default.")
+	flag.StringVar(&healthAddr, "health-addr", ":9440",
+		"The address the health probe endpoints bind to. Empty disables them.")
+	flag.StringVar(&probeRegion, "probe-region", "cn-hangzhou",
+		"The region whose endpoint the readiness probe validates the Alibaba Cloud credentials against.")
+	flag.DurationVar(&probeInterval, "probe-interval", time.Minute,
+		"How long a readiness check result is reused before the credentials are validated again.")
+	flag.StringVar(&clusterSelector, "cluster-selector", "",
+		"Label selector on CAPI Clusters; only matching clusters and their machines are reconciled. "+
+			"Empty selects every cluster. Used to shard clusters across provider instances.")
 	flag.Parse()
 
 	ctrl.SetLogger(zap.New(func(o *zap.Options) {
 		o.Development = true
 	}))
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	if aliyun.RateLimitQPS <= 0 || aliyun.RateLimitBurst < 1 {
+		setupLog.Error(nil, "invalid cloud API rate limit", "qps", aliyun.RateLimitQPS, "burst", aliyun.RateLimitBurst)
+		os.Exit(1)
+	}
+
+	var selector labels.Selector
+	if len(clusterSelector) > 0 {
+		var err error
+		if selector, err = labels.Parse(clusterSelector); err != nil {
+			setupLog.Error(err, "invalid cluster selector", "selector", clusterSelector)
+			os.Exit(1)
+		}
+	}
+
+	options := ctrl.Options{
 		Scheme:             scheme,
 		MetricsBindAddress: metricsAddr,
 		LeaderElection:     enableLeaderElection,
-		Port:               9443,
-	})
+		SyncPeriod:         &syncPeriod,
+		Port:               webhookPort,
+		CertDir:            webhookCertDir,
+	}
+	if namespaces := splitNamespaces(watchNamespace); len(namespaces) == 1 {
+		options.Namespace = namespaces[0]
+	} else if len(namespaces) > 1 {
+		options.NewCache = cache.MultiNamespacedCacheBuilder(namespaces)
+	}
+
+	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), options)
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
+	if len(healthAddr) > 0 {
+		regionClient, err := aliyun.NewRegionClient(ctrl.Log.WithName("probe"), probeRegion)
+		if err != nil {
+			setupLog.Error(err, "unable to create region client", "region", probeRegion)
+			os.Exit(1)
+		}
+		if err = mgr.Add(&healthz.Server{
+			Addr: healthAddr,
+			ReadyChecks: map[string]healthz.Check{
+				"credentials": healthz.Cached(probeInterval, regionClient.CheckCredentials),
+			},
+			Log: ctrl.Log.WithName("healthz"),
+		}); err != nil {
+			setupLog.Error(err, "unable to add health probes")
+			os.Exit(1)
+		}
+	}
+
+	if err = metrics.RegisterPhaseCollector(mgr.GetClient()); err != nil {
+		setupLog.Error(err, "unable to register metrics collector")
+		os.Exit(1)
+	}
+
 	if err = (&controllers.AlicloudMachineReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("AlicloudMachine"),
-		Scheme: mgr.GetScheme(),
+		Client:       mgr.GetClient(),
+		Log:          ctrl.Log.WithName("controllers").WithName("AlicloudMachine"),
+		Scheme:       mgr.GetScheme(),
+		Recorder:     mgr.GetEventRecorderFor("alicloudmachine-controller"),
+		ResyncPeriod: machineResyncPeriod,
+
+		MaxConcurrentReconciles: machineConcurrency,
+		ClusterSelector:         selector,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AlicloudMachine")
 		os.Exit(1)
 	}
 	if err = (&controllers.AlicloudClusterReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("AlicloudCluster"),
-		Scheme: mgr.GetScheme(),
+		Client:       mgr.GetClient(),
+		Log:          ctrl.Log.WithName("controllers").WithName("AlicloudCluster"),
+		Scheme:       mgr.GetScheme(),
+		Recorder:     mgr.GetEventRecorderFor("alicloudcluster-controller"),
+		ResyncPeriod: clusterResyncPeriod,
+
+		MaxConcurrentReconciles: clusterConcurrency,
+		ClusterSelector:         selector,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AlicloudCluster")
 		os.Exit(1)
@@ -91,3 +196,14 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// splitNamespaces splits a comma separated namespace list, dropping empty entries.
+func splitNamespaces(list string) []string {
+	var namespaces []string
+	for _, ns := range strings.Split(list, ",") {
+		if ns = strings.TrimSpace(ns); len(ns) > 0 {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return namespaces
+}
@@ -0,0 +1,164 @@
+package aliyun
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
+	infrav1 "sigs.k8s.io/cluster-api-provider-alicloud/api/v1alpha2"
+	"sigs.k8s.io/cluster-api-provider-alicloud/pkg/aliyun/retry"
+)
+
+func NewBastionClient(logger logr.Logger, regionID string) (*BastionClient, error) {
+	cli, err := ecs.NewClientWithAccessKey(regionID, AccessKeyId, AccessKeySecret)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create bastion client")
+	}
+	return &BastionClient{
+		Logger:   logger.WithValues("client", "bastion"),
+		cli:      cli,
+		limiter:  Limiter(regionID, "ecs"),
+		regionID: regionID,
+	}, nil
+}
+
+type BastionClient struct {
+	logr.Logger
+	cli      *ecs.Client
+	limiter  *rate.Limiter
+	regionID string
+}
+
+func (s *BastionClient) Describe(id string) (*ecs.Instance, error) {
+	logger := s.WithValues("SDKAction", "Describe", "id", id)
+
+	raw, err := json.Marshal([]string{id})
+	if err != nil {
+		return nil, errors.Wrap(err, "json.Marshal")
+	}
+
+	req := ecs.CreateDescribeInstancesRequest()
+	req.Scheme = "https"
+	req.RegionId = s.regionID
+	req.InstanceIds = string(raw)
+
+	var resp *ecs.DescribeInstancesResponse
+	if err := retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting")
+		err := Call(s.limiter, "ecs", "DescribeInstances", func() error {
+			var err error
+			resp, err = s.cli.DescribeInstances(req)
+			return err
+		})
+		if err != nil {
+			logger.Info("error: " + err.Error())
+		}
+		return errors.Wrap(err, "DescribeInstances")
+	}); err != nil {
+		if retry.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	logger.Info("success", "TotalCount", resp.TotalCount)
+	if len(resp.Instances.Instance) == 0 {
+		return nil, nil
+	}
+	return &resp.Instances.Instance[0], nil
+}
+
+func (s *BastionClient) Create(spec infrav1.BastionSpec, zoneID, vswitchID, sgID string) (string, error) {
+	logger := s.WithValues("SDKAction", "Create")
+
+	req := spec.ConvertToRunInstancesReq(s.regionID, zoneID, vswitchID, sgID)
+	var resp *ecs.RunInstancesResponse
+	if err := retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting", "request", req)
+		err := Call(s.limiter, "ecs", "RunInstances", func() error {
+			var err error
+			resp, err = s.cli.RunInstances(req)
+			return err
+		})
+		if err != nil {
+			logger.Info("error: " + err.Error())
+		}
+		return errors.Wrap(err, "RunInstances")
+	}); err != nil {
+		return "", err
+	}
+
+	if len(resp.InstanceIdSets.InstanceIdSet) == 0 {
+		return "", errors.Errorf("RunInstances returned no instance (RequestId: %s)", resp.RequestId)
+	}
+	id := resp.InstanceIdSets.InstanceIdSet[0]
+	logger.Info("success", "InstanceId", id)
+	return id, nil
+}
+
+func (s *BastionClient) WaitRunning(id string) (*ecs.Instance, error) {
+	logger := s.WithValues("SDKAction", "WaitRunning", "id", id)
+
+	var ret *ecs.Instance
+	if err := retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("describing")
+		var err error
+		ret, err = s.Describe(id)
+		if err != nil {
+			logger.Info("error: " + err.Error())
+			return errors.Wrap(err, "Describe")
+		}
+
+		if ret == nil {
+			logger.Info("nil result")
+			return retry.ErrRetry
+		}
+
+		if ret.Status != "Running" {
+			logger.Info(fmt.Sprintf("waiting for status: Running, now status: %v", ret.Status))
+			return retry.ErrRetry
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	logger.Info("ready")
+	return ret, nil
+}
+
+func (s *BastionClient) Delete(id string) error {
+	logger := s.WithValues("SDKAction", "Delete", "id", id)
+
+	req := ecs.CreateDeleteInstanceRequest()
+	req.Scheme = "https"
+	req.InstanceId = id
+	req.Force = requests.NewBoolean(true)
+
+	if err := retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting")
+		err := Call(s.limiter, "ecs", "DeleteInstance", func() error {
+			_, err := s.cli.DeleteInstance(req)
+			return err
+		})
+		if err != nil {
+			if retry.IsNotFound(err) {
+				return nil
+			}
+			if retry.IsConflict(err) {
+				return retry.ErrRetry
+			}
+			logger.Info("error: " + err.Error())
+		}
+		return errors.Wrap(err, "DeleteInstance")
+	}); err != nil {
+		return err
+	}
+
+	logger.Info("success")
+	return nil
+}
@@ -0,0 +1,28 @@
+package aliyun
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
+	"sigs.k8s.io/cluster-api-provider-alicloud/pkg/aliyun/metrics"
+)
+
+// Call runs fn as the Alibaba Cloud API action of service once limiter grants a token, and records
+// its latency and outcome. Every SDK call goes through it so that none escapes the account rate
+// limit or the API metrics.
+func Call(limiter *rate.Limiter, service, action string, fn func() error) error {
+	if err := limiter.Wait(context.TODO()); err != nil {
+		return errors.Wrapf(err, "wait rate limiter for %s", action)
+	}
+	return observe(service, action, fn)
+}
+
+// observe runs fn as the Alibaba Cloud API action of service and records its latency and outcome.
+func observe(service, action string, fn func() error) error {
+	start := time.Now()
+	err := fn()
+	metrics.ObserveAPICall(service, action, start, err)
+	return err
+}
@@ -0,0 +1,290 @@
+package aliyun
+
+import (
+	"fmt"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/cbn"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
+	"sigs.k8s.io/cluster-api-provider-alicloud/pkg/aliyun/retry"
+)
+
+const (
+	CenChildInstanceTypeVPC = "VPC"
+
+	CenAttached  = "Attached"
+	CenPublished = "Published"
+)
+
+func NewCENClient(logger logr.Logger, regionID string) (*CENClient, error) {
+	cli, err := cbn.NewClientWithAccessKey(regionID, AccessKeyId, AccessKeySecret)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create cen client")
+	}
+	return &CENClient{
+		Logger:   logger.WithValues("client", "cen"),
+		cli:      cli,
+		limiter:  Limiter(regionID, "cbn"),
+		regionID: regionID,
+	}, nil
+}
+
+// CENClient attaches VPCs of its region to Cloud Enterprise Network instances.
+type CENClient struct {
+	logr.Logger
+	cli      *cbn.Client
+	limiter  *rate.Limiter
+	regionID string
+}
+
+// DescribeAttachment returns the attachment of the VPC to the CEN instance, or nil if it is not attached.
+func (s *CENClient) DescribeAttachment(cenID, vpcID string) (*cbn.DescribeCenAttachedChildInstanceAttributeResponse, error) {
+	logger := s.WithValues("SDKAction", "DescribeAttachment", "cen", cenID, "vpc", vpcID)
+
+	req := cbn.CreateDescribeCenAttachedChildInstanceAttributeRequest()
+	req.Scheme = "https"
+	req.CenId = cenID
+	req.ChildInstanceId = vpcID
+	req.ChildInstanceType = CenChildInstanceTypeVPC
+	req.ChildInstanceRegionId = s.regionID
+
+	var resp *cbn.DescribeCenAttachedChildInstanceAttributeResponse
+	if err := retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting")
+		err := Call(s.limiter, "cbn", "DescribeCenAttachedChildInstanceAttribute", func() error {
+			var err error
+			resp, err = s.cli.DescribeCenAttachedChildInstanceAttribute(req)
+			return err
+		})
+		if err != nil {
+			logger.Info("error: " + err.Error())
+		}
+		return errors.Wrap(err, "DescribeCenAttachedChildInstanceAttribute")
+	}); err != nil {
+		if retry.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if len(resp.ChildInstanceId) == 0 {
+		return nil, nil
+	}
+	logger.Info("success", "Status", resp.Status)
+	return resp, nil
+}
+
+func (s *CENClient) Attach(cenID, vpcID string) error {
+	logger := s.WithValues("SDKAction", "Attach", "cen", cenID, "vpc", vpcID)
+
+	req := cbn.CreateAttachCenChildInstanceRequest()
+	req.Scheme = "https"
+	req.CenId = cenID
+	req.ChildInstanceId = vpcID
+	req.ChildInstanceType = CenChildInstanceTypeVPC
+	req.ChildInstanceRegionId = s.regionID
+
+	return retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting")
+		err := Call(s.limiter, "cbn", "AttachCenChildInstance", func() error {
+			_, err := s.cli.AttachCenChildInstance(req)
+			return err
+		})
+		if err != nil {
+			if retry.IsConflict(err) {
+				return retry.ErrRetry
+			}
+
+			logger.Info("error: " + err.Error())
+			return errors.Wrap(err, "AttachCenChildInstance")
+		}
+
+		logger.Info("success")
+		return nil
+	})
+}
+
+func (s *CENClient) WaitAttached(cenID, vpcID string) error {
+	logger := s.WithValues("SDKAction", "WaitAttached", "cen", cenID, "vpc", vpcID)
+
+	if err := retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("describing")
+		ret, err := s.DescribeAttachment(cenID, vpcID)
+		if err != nil {
+			logger.Info("error: " + err.Error())
+			return errors.Wrap(err, "DescribeAttachment")
+		}
+		if ret == nil {
+			logger.Info("nil result")
+			return retry.ErrRetry
+		}
+		if ret.Status != CenAttached {
+			logger.Info(fmt.Sprintf("waiting for status: %v, now status: %v", CenAttached, ret.Status))
+			return retry.ErrRetry
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	logger.Info("ready")
+	return nil
+}
+
+// Detach detaches the VPC and waits until the attachment is gone.
+func (s *CENClient) Detach(cenID, vpcID string) error {
+	logger := s.WithValues("SDKAction", "Detach", "cen", cenID, "vpc", vpcID)
+
+	req := cbn.CreateDetachCenChildInstanceRequest()
+	req.Scheme = "https"
+	req.CenId = cenID
+	req.ChildInstanceId = vpcID
+	req.ChildInstanceType = CenChildInstanceTypeVPC
+	req.ChildInstanceRegionId = s.regionID
+
+	if err := retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting")
+		err := Call(s.limiter, "cbn", "DetachCenChildInstance", func() error {
+			_, err := s.cli.DetachCenChildInstance(req)
+			return err
+		})
+		if err != nil {
+			if retry.IsNotFound(err) {
+				return nil
+			}
+			if retry.IsConflict(err) {
+				return retry.ErrRetry
+			}
+			logger.Info("error: " + err.Error())
+		}
+		return errors.Wrap(err, "DetachCenChildInstance")
+	}); err != nil {
+		return err
+	}
+
+	return retry.Try(retry.DefaultBackOf, func() error {
+		ret, err := s.DescribeAttachment(cenID, vpcID)
+		if err != nil {
+			return err
+		}
+		if ret != nil {
+			logger.Info("waiting for detach", "status", ret.Status)
+			return retry.ErrRetry
+		}
+		logger.Info("success")
+		return nil
+	})
+}
+
+// DescribePublishedRoutes returns the publish status of the route entries of the VPC route table, by destination CIDR.
+func (s *CENClient) DescribePublishedRoutes(cenID, vpcID, routeTableID string) (map[string]string, error) {
+	logger := s.WithValues("SDKAction", "DescribePublishedRoutes", "cen", cenID, "vpc", vpcID)
+
+	req := cbn.CreateDescribePublishedRouteEntriesRequest()
+	req.Scheme = "https"
+	req.CenId = cenID
+	req.ChildInstanceId = vpcID
+	req.ChildInstanceType = CenChildInstanceTypeVPC
+	req.ChildInstanceRegionId = s.regionID
+	req.ChildInstanceRouteTableId = routeTableID
+	req.PageSize = requests.NewInteger(50)
+
+	ret := map[string]string{}
+	for page := 1; ; page++ {
+		req.PageNumber = requests.NewInteger(page)
+		var resp *cbn.DescribePublishedRouteEntriesResponse
+		if err := retry.Try(retry.DefaultBackOf, func() error {
+			logger.Info("requesting", "PageNumber", page)
+			err := Call(s.limiter, "cbn", "DescribePublishedRouteEntries", func() error {
+				var err error
+				resp, err = s.cli.DescribePublishedRouteEntries(req)
+				return err
+			})
+			if err != nil {
+				logger.Info("error: " + err.Error())
+			}
+			return errors.Wrap(err, "DescribePublishedRouteEntries")
+		}); err != nil {
+			return nil, err
+		}
+
+		for _, e := range resp.PublishedRouteEntries.PublishedRouteEntry {
+			ret[e.DestinationCidrBlock] = e.PublishStatus
+		}
+		if page*resp.PageSize >= resp.TotalCount || len(resp.PublishedRouteEntries.PublishedRouteEntry) == 0 {
+			break
+		}
+	}
+
+	logger.Info("success", "count", len(ret))
+	return ret, nil
+}
+
+func (s *CENClient) PublishRoute(cenID, vpcID, routeTableID, cidr string) error {
+	logger := s.WithValues("SDKAction", "PublishRoute", "cen", cenID, "vpc", vpcID, "cidr", cidr)
+
+	req := cbn.CreatePublishRouteEntriesRequest()
+	req.Scheme = "https"
+	req.CenId = cenID
+	req.ChildInstanceId = vpcID
+	req.ChildInstanceType = CenChildInstanceTypeVPC
+	req.ChildInstanceRegionId = s.regionID
+	req.ChildInstanceRouteTableId = routeTableID
+	req.DestinationCidrBlock = cidr
+
+	return retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting")
+		err := Call(s.limiter, "cbn", "PublishRouteEntries", func() error {
+			_, err := s.cli.PublishRouteEntries(req)
+			return err
+		})
+		if err != nil {
+			if retry.IsConflict(err) {
+				return retry.ErrRetry
+			}
+
+			logger.Info("error: " + err.Error())
+			return errors.Wrap(err, "PublishRouteEntries")
+		}
+
+		logger.Info("success")
+		return nil
+	})
+}
+
+func (s *CENClient) WithdrawRoute(cenID, vpcID, routeTableID, cidr string) error {
+	logger := s.WithValues("SDKAction", "WithdrawRoute", "cen", cenID, "vpc", vpcID, "cidr", cidr)
+
+	req := cbn.CreateWithdrawPublishedRouteEntriesRequest()
+	req.Scheme = "https"
+	req.CenId = cenID
+	req.ChildInstanceId = vpcID
+	req.ChildInstanceType = CenChildInstanceTypeVPC
+	req.ChildInstanceRegionId = s.regionID
+	req.ChildInstanceRouteTableId = routeTableID
+	req.DestinationCidrBlock = cidr
+
+	return retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting")
+		err := Call(s.limiter, "cbn", "WithdrawPublishedRouteEntries", func() error {
+			_, err := s.cli.WithdrawPublishedRouteEntries(req)
+			return err
+		})
+		if err != nil {
+			if retry.IsNotFound(err) {
+				return nil
+			}
+			if retry.IsConflict(err) {
+				return retry.ErrRetry
+			}
+
+			logger.Info("error: " + err.Error())
+			return errors.Wrap(err, "WithdrawPublishedRouteEntries")
+		}
+
+		logger.Info("success")
+		return nil
+	})
+}
@@ -0,0 +1,18 @@
+package aliyun
+
+import (
+	sdkerr "github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
+	"github.com/pkg/errors"
+)
+
+// ErrorDetail unwraps err and returns the Alibaba Cloud error code, request ID and message carried by it.
+// Empty strings are returned for errors that did not come from the SDK.
+func ErrorDetail(err error) (code, requestID, message string) {
+	switch e := errors.Cause(err).(type) {
+	case *sdkerr.ServerError:
+		return e.ErrorCode(), e.RequestId(), e.Message()
+	case sdkerr.Error:
+		return e.ErrorCode(), "", e.Message()
+	}
+	return "", "", ""
+}
@@ -0,0 +1,204 @@
+package aliyun
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// maxInstanceBatch is the largest number of ids DescribeInstances accepts in one call.
+	maxInstanceBatch = 100
+	// instanceBatchWindow is how long a lookup waits for other lookups to join its batch.
+	instanceBatchWindow = 50 * time.Millisecond
+)
+
+// InstanceCacheTTL is how long a described instance is served from the cache.
+var InstanceCacheTTL = 5 * time.Second
+
+var (
+	instanceCachesMu sync.Mutex
+	instanceCaches   = map[string]*InstanceCache{}
+)
+
+// InstanceCacheFor returns the instance cache shared by all reconciles of the configured account in regionID.
+func InstanceCacheFor(regionID string) (*InstanceCache, error) {
+	key := strings.Join([]string{AccessKeyId, regionID}, "/")
+
+	instanceCachesMu.Lock()
+	defer instanceCachesMu.Unlock()
+
+	if c, ok := instanceCaches[key]; ok {
+		return c, nil
+	}
+	cli, err := GetEcsClient(regionID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create ecs client")
+	}
+	c := &InstanceCache{
+		regionID:    regionID,
+		cli:         cli,
+		limiter:     Limiter(regionID, "ecs"),
+		entries:     map[string]instanceEntry{},
+		pending:     map[string]*instanceCall{},
+		invalidated: map[string]bool{},
+	}
+	instanceCaches[key] = c
+	return c, nil
+}
+
+type instanceEntry struct {
+	instance *ecs.Instance
+	expires  time.Time
+}
+
+type instanceCall struct {
+	done     chan struct{}
+	instance *ecs.Instance
+	err      error
+}
+
+// InstanceCache coalesces concurrent instance lookups into batched DescribeInstances calls
+// and serves the results for InstanceCacheTTL, so a reconcile storm after a manager restart
+// costs one API call per hundred machines instead of one per machine.
+type InstanceCache struct {
+	regionID string
+	cli      *ecs.Client
+	limiter  *rate.Limiter
+
+	mu        sync.Mutex
+	entries   map[string]instanceEntry
+	pending   map[string]*instanceCall
+	scheduled bool
+	// inflight counts the batches being described, and invalidated records the ids invalidated meanwhile
+	// so that a lookup started before the invalidation does not put its stale result back into the cache.
+	inflight    int
+	invalidated map[string]bool
+}
+
+// Get returns the instance with the given id, or nil if it does not exist.
+// The returned instance is shared with other callers and must not be modified.
+func (c *InstanceCache) Get(id string) (*ecs.Instance, error) {
+	c.mu.Lock()
+	if e, ok := c.entries[id]; ok && time.Now().Before(e.expires) {
+		c.mu.Unlock()
+		return e.instance, nil
+	}
+
+	call, ok := c.pending[id]
+	if !ok {
+		call = &instanceCall{done: make(chan struct{})}
+		c.pending[id] = call
+		if len(c.pending) >= maxInstanceBatch {
+			go c.flush()
+		} else if !c.scheduled {
+			c.scheduled = true
+			time.AfterFunc(instanceBatchWindow, c.flush)
+		}
+	}
+	c.mu.Unlock()
+
+	<-call.done
+	return call.instance, call.err
+}
+
+// Invalidate drops the cached state of id, e.g. after the instance was created, started or deleted.
+func (c *InstanceCache) Invalidate(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, id)
+	if c.inflight > 0 {
+		c.invalidated[id] = true
+	}
+}
+
+func (c *InstanceCache) flush() {
+	c.mu.Lock()
+	c.scheduled = false
+	batch := make(map[string]*instanceCall, maxInstanceBatch)
+	for id, call := range c.pending {
+		if len(batch) == maxInstanceBatch {
+			break
+		}
+		batch[id] = call
+		delete(c.pending, id)
+	}
+	if len(c.pending) > 0 {
+		c.scheduled = true
+		go c.flush()
+	}
+	if len(batch) == 0 {
+		c.mu.Unlock()
+		return
+	}
+	c.inflight++
+	c.mu.Unlock()
+
+	ids := make([]string, 0, len(batch))
+	for id := range batch {
+		ids = append(ids, id)
+	}
+	instances, err := c.describe(ids)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for id, e := range c.entries {
+		if now.After(e.expires) {
+			delete(c.entries, id)
+		}
+	}
+	for id, call := range batch {
+		if err != nil {
+			call.err = err
+		} else {
+			call.instance = instances[id]
+			if !c.invalidated[id] {
+				c.entries[id] = instanceEntry{instance: call.instance, expires: now.Add(InstanceCacheTTL)}
+			}
+		}
+		close(call.done)
+	}
+	c.inflight--
+	if c.inflight == 0 {
+		c.invalidated = map[string]bool{}
+	}
+}
+
+func (c *InstanceCache) describe(ids []string) (map[string]*ecs.Instance, error) {
+	raw, err := json.Marshal(ids)
+	if err != nil {
+		return nil, errors.Wrap(err, "json.Marshal")
+	}
+
+	req := ecs.CreateDescribeInstancesRequest()
+	req.Scheme = "https"
+	req.RegionId = c.regionID
+	req.InstanceIds = string(raw)
+	req.PageSize = requests.NewInteger(maxInstanceBatch)
+
+	// a single attempt: every Get waiting on this batch blocks until it returns, so a throttled
+	// account fails the waiting reconciles fast and leaves the retry to their requeue
+	var resp *ecs.DescribeInstancesResponse
+	if err := Call(c.limiter, "ecs", "DescribeInstances", func() error {
+		var err error
+		resp, err = c.cli.DescribeInstances(req)
+		return err
+	}); err != nil {
+		return nil, errors.Wrap(err, "DescribeInstances")
+	}
+
+	ret := make(map[string]*ecs.Instance, len(resp.Instances.Instance))
+	for i := range resp.Instances.Instance {
+		instance := resp.Instances.Instance[i]
+		ret[instance.InstanceId] = &instance
+	}
+	return ret, nil
+}
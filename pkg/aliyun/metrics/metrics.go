@@ -0,0 +1,97 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/cluster-api-provider-alicloud/pkg/aliyun/retry"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const namespace = "alicloud"
+
+var (
+	apiRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "api",
+		Name:      "requests_total",
+		Help:      "Total number of Alibaba Cloud API calls by service and action.",
+	}, []string{"service", "action"})
+
+	apiRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "api",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of Alibaba Cloud API calls by service and action.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"service", "action"})
+
+	apiErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "api",
+		Name:      "errors_total",
+		Help:      "Total number of failed Alibaba Cloud API calls by service, action and error code.",
+	}, []string{"service", "action", "code"})
+
+	apiThrottledTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "api",
+		Name:      "throttled_total",
+		Help:      "Total number of Alibaba Cloud API calls rejected by throttling, by service and action.",
+	}, []string{"service", "action"})
+
+	clusterTimeToReady = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "cluster",
+		Name:      "time_to_ready_seconds",
+		Help:      "Time from AlicloudCluster creation until its infrastructure became ready.",
+		Buckets:   prometheus.ExponentialBuckets(30, 2, 8),
+	})
+
+	machineTimeToReady = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "machine",
+		Name:      "time_to_ready_seconds",
+		Help:      "Time from AlicloudMachine creation until its instance became ready.",
+		Buckets:   prometheus.ExponentialBuckets(30, 2, 8),
+	})
+)
+
+func init() {
+	crmetrics.Registry.MustRegister(
+		apiRequestsTotal,
+		apiRequestDuration,
+		apiErrorsTotal,
+		apiThrottledTotal,
+		clusterTimeToReady,
+		machineTimeToReady,
+	)
+}
+
+// ObserveAPICall records the outcome of a single SDK call started at start.
+func ObserveAPICall(service, action string, start time.Time, err error) {
+	apiRequestsTotal.WithLabelValues(service, action).Inc()
+	apiRequestDuration.WithLabelValues(service, action).Observe(time.Since(start).Seconds())
+	if err == nil {
+		return
+	}
+
+	code := retry.Code(err)
+	if len(code) == 0 {
+		code = "Unknown"
+	}
+	apiErrorsTotal.WithLabelValues(service, action, code).Inc()
+	if retry.IsThrottled(err) {
+		apiThrottledTotal.WithLabelValues(service, action).Inc()
+	}
+}
+
+// ObserveClusterReady records how long a cluster took to become ready.
+func ObserveClusterReady(d time.Duration) {
+	clusterTimeToReady.Observe(d.Seconds())
+}
+
+// ObserveMachineReady records how long a machine took to become ready.
+func ObserveMachineReady(d time.Duration) {
+	machineTimeToReady.Observe(d.Seconds())
+}
@@ -0,0 +1,282 @@
+package aliyun
+
+import (
+	"fmt"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
+	infrav1 "sigs.k8s.io/cluster-api-provider-alicloud/api/v1alpha2"
+	"sigs.k8s.io/cluster-api-provider-alicloud/pkg/aliyun/retry"
+)
+
+const NetworkAclResourceVSwitch = "VSwitch"
+
+func NewNetworkAclClient(logger logr.Logger, regionID string) (*NetworkAclClient, error) {
+	cli, err := vpc.NewClientWithAccessKey(regionID, AccessKeyId, AccessKeySecret)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create networkAcl client")
+	}
+	return &NetworkAclClient{
+		Logger:  logger.WithValues("client", "networkAcl"),
+		cli:     cli,
+		limiter: Limiter(regionID, "vpc"),
+	}, nil
+}
+
+type NetworkAclClient struct {
+	logr.Logger
+	cli     *vpc.Client
+	limiter *rate.Limiter
+}
+
+// Describe returns the network ACL with its entries and associated resources, or nil if it does not exist.
+func (s *NetworkAclClient) Describe(id string) (*vpc.NetworkAclAttribute, error) {
+	logger := s.WithValues("SDKAction", "Describe", "id", id)
+
+	req := vpc.CreateDescribeNetworkAclAttributesRequest()
+	req.Scheme = "https"
+	req.NetworkAclId = id
+
+	var resp *vpc.DescribeNetworkAclAttributesResponse
+	if err := retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting")
+		err := Call(s.limiter, "vpc", "DescribeNetworkAclAttributes", func() error {
+			var err error
+			resp, err = s.cli.DescribeNetworkAclAttributes(req)
+			return err
+		})
+		if err != nil {
+			logger.Info("error: " + err.Error())
+		}
+		return errors.Wrap(err, "DescribeNetworkAclAttributes")
+	}); err != nil {
+		if retry.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if len(resp.NetworkAclAttribute.NetworkAclId) == 0 {
+		return nil, nil
+	}
+	logger.Info("success", "Status", resp.NetworkAclAttribute.Status)
+	return &resp.NetworkAclAttribute, nil
+}
+
+func (s *NetworkAclClient) Create(spec infrav1.NetworkAclSpec, vpcID string) (string, error) {
+	logger := s.WithValues("SDKAction", "Create")
+
+	req := spec.ConvertToCreateReq(vpcID)
+	var resp *vpc.CreateNetworkAclResponse
+	if err := retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting", "request", req)
+		err := Call(s.limiter, "vpc", "CreateNetworkAcl", func() error {
+			var err error
+			resp, err = s.cli.CreateNetworkAcl(req)
+			return err
+		})
+		if err != nil {
+			if retry.IsConflict(err) {
+				return retry.ErrRetry
+			}
+			logger.Info("error: " + err.Error())
+		}
+		return errors.Wrap(err, "CreateNetworkAcl")
+	}); err != nil {
+		return "", err
+	}
+
+	logger.Info("success", "NetworkAclId", resp.NetworkAclId)
+	return resp.NetworkAclId, nil
+}
+
+func (s *NetworkAclClient) WaitReady(id string) (*vpc.NetworkAclAttribute, error) {
+	logger := s.WithValues("SDKAction", "WaitReady", "id", id)
+
+	var ret *vpc.NetworkAclAttribute
+	if err := retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("describing")
+		var err error
+		ret, err = s.Describe(id)
+		if err != nil {
+			logger.Info("error: " + err.Error())
+			return errors.Wrap(err, "Describe")
+		}
+		if ret == nil {
+			logger.Info("nil result")
+			return retry.ErrRetry
+		}
+		if ret.Status != infrav1.Available {
+			logger.Info(fmt.Sprintf("waiting for status: %v, now status: %v", infrav1.Available, ret.Status))
+			return retry.ErrRetry
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	logger.Info("ready")
+	return ret, nil
+}
+
+func (s *NetworkAclClient) Delete(id string) error {
+	logger := s.WithValues("SDKAction", "Delete", "id", id)
+
+	req := vpc.CreateDeleteNetworkAclRequest()
+	req.Scheme = "https"
+	req.NetworkAclId = id
+
+	if err := retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting")
+		err := Call(s.limiter, "vpc", "DeleteNetworkAcl", func() error {
+			_, err := s.cli.DeleteNetworkAcl(req)
+			return err
+		})
+		if err != nil {
+			if retry.IsNotFound(err) {
+				return nil
+			}
+			if retry.IsConflict(err) {
+				return retry.ErrRetry
+			}
+			logger.Info("error: " + err.Error())
+		}
+		return errors.Wrap(err, "DeleteNetworkAcl")
+	}); err != nil {
+		return err
+	}
+
+	logger.Info("success")
+	return nil
+}
+
+func (s *NetworkAclClient) Associate(id, vswitchID string) error {
+	logger := s.WithValues("SDKAction", "Associate", "id", id, "vswitch", vswitchID)
+
+	req := vpc.CreateAssociateNetworkAclRequest()
+	req.Scheme = "https"
+	req.NetworkAclId = id
+	req.Resource = &[]vpc.AssociateNetworkAclResource{{
+		ResourceType: NetworkAclResourceVSwitch,
+		ResourceId:   vswitchID,
+	}}
+
+	return retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting")
+		err := Call(s.limiter, "vpc", "AssociateNetworkAcl", func() error {
+			_, err := s.cli.AssociateNetworkAcl(req)
+			return err
+		})
+		if err != nil {
+			if retry.IsConflict(err) {
+				return retry.ErrRetry
+			}
+
+			logger.Info("error: " + err.Error())
+			return errors.Wrap(err, "AssociateNetworkAcl")
+		}
+
+		logger.Info("success")
+		return nil
+	})
+}
+
+func (s *NetworkAclClient) Unassociate(id, vswitchID string) error {
+	logger := s.WithValues("SDKAction", "Unassociate", "id", id, "vswitch", vswitchID)
+
+	req := vpc.CreateUnassociateNetworkAclRequest()
+	req.Scheme = "https"
+	req.NetworkAclId = id
+	req.Resource = &[]vpc.UnassociateNetworkAclResource{{
+		ResourceType: NetworkAclResourceVSwitch,
+		ResourceId:   vswitchID,
+	}}
+
+	return retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting")
+		err := Call(s.limiter, "vpc", "UnassociateNetworkAcl", func() error {
+			_, err := s.cli.UnassociateNetworkAcl(req)
+			return err
+		})
+		if err != nil {
+			if retry.IsNotFound(err) {
+				return nil
+			}
+			if retry.IsConflict(err) {
+				return retry.ErrRetry
+			}
+
+			logger.Info("error: " + err.Error())
+			return errors.Wrap(err, "UnassociateNetworkAcl")
+		}
+
+		logger.Info("success")
+		return nil
+	})
+}
+
+// WaitUnassociated waits until the VSwitch no longer shows up among the resources of the network ACL.
+func (s *NetworkAclClient) WaitUnassociated(id, vswitchID string) error {
+	logger := s.WithValues("SDKAction", "WaitUnassociated", "id", id, "vswitch", vswitchID)
+
+	return retry.Try(retry.DefaultBackOf, func() error {
+		target, err := s.Describe(id)
+		if err != nil {
+			return errors.Wrap(err, "Describe")
+		}
+		if target == nil {
+			return nil
+		}
+		for _, r := range target.Resources.Resource {
+			if r.ResourceId == vswitchID {
+				logger.Info("waiting", "status", r.Status)
+				return retry.ErrRetry
+			}
+		}
+		return nil
+	})
+}
+
+// UpdateEntries replaces the custom ingress and egress entries of the network ACL, in order.
+func (s *NetworkAclClient) UpdateEntries(id string, ingress, egress []infrav1.NetworkAclEntrySpec) error {
+	logger := s.WithValues("SDKAction", "UpdateEntries", "id", id)
+
+	req := vpc.CreateUpdateNetworkAclEntriesRequest()
+	req.Scheme = "https"
+	req.NetworkAclId = id
+	req.UpdateIngressAclEntries = requests.NewBoolean(true)
+	req.UpdateEgressAclEntries = requests.NewBoolean(true)
+	ingressEntries := make([]vpc.UpdateNetworkAclEntriesIngressAclEntries, 0, len(ingress))
+	for i := range ingress {
+		ingressEntries = append(ingressEntries, ingress[i].ToIngress())
+	}
+	egressEntries := make([]vpc.UpdateNetworkAclEntriesEgressAclEntries, 0, len(egress))
+	for i := range egress {
+		egressEntries = append(egressEntries, egress[i].ToEgress())
+	}
+	req.IngressAclEntries = &ingressEntries
+	req.EgressAclEntries = &egressEntries
+
+	if err := retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting", "ingress", len(ingress), "egress", len(egress))
+		err := Call(s.limiter, "vpc", "UpdateNetworkAclEntries", func() error {
+			_, err := s.cli.UpdateNetworkAclEntries(req)
+			return err
+		})
+		if err != nil {
+			if retry.IsConflict(err) {
+				return retry.ErrRetry
+			}
+			logger.Info("error: " + err.Error())
+		}
+		return errors.Wrap(err, "UpdateNetworkAclEntries")
+	}); err != nil {
+		return err
+	}
+
+	logger.Info("success")
+	return nil
+}
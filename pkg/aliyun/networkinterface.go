@@ -0,0 +1,110 @@
+package aliyun
+
+import (
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
+	"sigs.k8s.io/cluster-api-provider-alicloud/pkg/aliyun/retry"
+)
+
+func NewNetworkInterfaceClient(logger logr.Logger, regionID string) (*NetworkInterfaceClient, error) {
+	cli, err := ecs.NewClientWithAccessKey(regionID, AccessKeyId, AccessKeySecret)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create networkInterface client")
+	}
+	return &NetworkInterfaceClient{
+		Logger:  logger.WithValues("client", "networkInterface"),
+		cli:     cli,
+		limiter: Limiter(regionID, "ecs"),
+	}, nil
+}
+
+type NetworkInterfaceClient struct {
+	logr.Logger
+	cli     *ecs.Client
+	limiter *rate.Limiter
+}
+
+// DescribeByInstance returns the secondary network interfaces attached to the instance.
+func (s *NetworkInterfaceClient) DescribeByInstance(instanceID string) ([]ecs.NetworkInterfaceSet, error) {
+	return s.describe(instanceID, "Secondary")
+}
+
+// DescribeIpv6Addresses returns the IPv6 addresses of all network interfaces of the instance.
+func (s *NetworkInterfaceClient) DescribeIpv6Addresses(instanceID string) ([]string, error) {
+	enis, err := s.describe(instanceID, "")
+	if err != nil {
+		return nil, err
+	}
+	var ret []string
+	for _, eni := range enis {
+		for _, ip := range eni.Ipv6Sets.Ipv6Set {
+			ret = append(ret, ip.Ipv6Address)
+		}
+	}
+	return ret, nil
+}
+
+func (s *NetworkInterfaceClient) describe(instanceID, eniType string) ([]ecs.NetworkInterfaceSet, error) {
+	logger := s.WithValues("SDKAction", "DescribeNetworkInterfaces", "instance", instanceID, "type", eniType)
+
+	req := ecs.CreateDescribeNetworkInterfacesRequest()
+	req.Scheme = "https"
+	req.InstanceId = instanceID
+	req.Type = eniType
+	req.PageSize = requests.NewInteger(50)
+
+	var resp *ecs.DescribeNetworkInterfacesResponse
+	if err := retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting")
+		err := Call(s.limiter, "ecs", "DescribeNetworkInterfaces", func() error {
+			var err error
+			resp, err = s.cli.DescribeNetworkInterfaces(req)
+			return err
+		})
+		if err != nil {
+			logger.Info("error: " + err.Error())
+		}
+		return errors.Wrap(err, "DescribeNetworkInterfaces")
+	}); err != nil {
+		return nil, err
+	}
+
+	logger.Info("success", "count", len(resp.NetworkInterfaceSets.NetworkInterfaceSet))
+	return resp.NetworkInterfaceSets.NetworkInterfaceSet, nil
+}
+
+// AssignPrivateIpAddresses assigns ips to the interface, or count addresses picked from its VSwitch when ips is empty.
+func (s *NetworkInterfaceClient) AssignPrivateIpAddresses(id string, ips []string, count int) error {
+	logger := s.WithValues("SDKAction", "AssignPrivateIpAddresses", "id", id)
+
+	req := ecs.CreateAssignPrivateIpAddressesRequest()
+	req.Scheme = "https"
+	req.NetworkInterfaceId = id
+	if len(ips) > 0 {
+		req.PrivateIpAddress = &ips
+	} else {
+		req.SecondaryPrivateIpAddressCount = requests.NewInteger(count)
+	}
+
+	return retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting", "ips", ips, "count", count)
+		err := Call(s.limiter, "ecs", "AssignPrivateIpAddresses", func() error {
+			_, err := s.cli.AssignPrivateIpAddresses(req)
+			return err
+		})
+		if err != nil {
+			// the interface cannot be changed while the instance is still starting
+			if retry.IsConflict(err) {
+				return retry.ErrRetry
+			}
+			logger.Info("error: " + err.Error())
+			return errors.Wrap(err, "AssignPrivateIpAddresses")
+		}
+
+		logger.Info("success")
+		return nil
+	})
+}
@@ -0,0 +1,292 @@
+package aliyun
+
+import (
+	"strconv"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/pvtz"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
+	"sigs.k8s.io/cluster-api-provider-alicloud/pkg/aliyun/retry"
+)
+
+const ZoneRecordTypeA = "A"
+
+func NewPrivateZoneClient(logger logr.Logger, regionID string) (*PrivateZoneClient, error) {
+	cli, err := pvtz.NewClientWithAccessKey(regionID, AccessKeyId, AccessKeySecret)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create privateZone client")
+	}
+	return &PrivateZoneClient{
+		Logger:   logger.WithValues("client", "privateZone"),
+		cli:      cli,
+		limiter:  Limiter(regionID, "pvtz"),
+		regionID: regionID,
+	}, nil
+}
+
+type PrivateZoneClient struct {
+	logr.Logger
+	cli      *pvtz.Client
+	limiter  *rate.Limiter
+	regionID string
+}
+
+// zoneNotFound reports whether err means the zone or record does not exist;
+// PrivateZone reports unknown IDs as invalid rather than missing.
+func zoneNotFound(err error) bool {
+	code := retry.Code(err)
+	return retry.IsNotFound(err) || code == "Zone.Invalid.Id" || code == "Record.Invalid.Id"
+}
+
+// Describe returns the zone with the VPCs bound to it, or nil if it does not exist.
+func (s *PrivateZoneClient) Describe(id string) (*pvtz.DescribeZoneInfoResponse, error) {
+	logger := s.WithValues("SDKAction", "Describe", "id", id)
+
+	req := pvtz.CreateDescribeZoneInfoRequest()
+	req.Scheme = "https"
+	req.ZoneId = id
+
+	var resp *pvtz.DescribeZoneInfoResponse
+	if err := retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting")
+		err := Call(s.limiter, "pvtz", "DescribeZoneInfo", func() error {
+			var err error
+			resp, err = s.cli.DescribeZoneInfo(req)
+			return err
+		})
+		if err != nil {
+			logger.Info("error: " + err.Error())
+		}
+		return errors.Wrap(err, "DescribeZoneInfo")
+	}); err != nil {
+		if zoneNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	logger.Info("success", "ZoneName", resp.ZoneName)
+	return resp, nil
+}
+
+func (s *PrivateZoneClient) Create(name string) (string, error) {
+	logger := s.WithValues("SDKAction", "Create", "name", name)
+
+	req := pvtz.CreateAddZoneRequest()
+	req.Scheme = "https"
+	req.ZoneName = name
+
+	var resp *pvtz.AddZoneResponse
+	if err := retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting")
+		err := Call(s.limiter, "pvtz", "AddZone", func() error {
+			var err error
+			resp, err = s.cli.AddZone(req)
+			return err
+		})
+		if err != nil {
+			logger.Info("error: " + err.Error())
+		}
+		return errors.Wrap(err, "AddZone")
+	}); err != nil {
+		return "", err
+	}
+
+	logger.Info("success", "ZoneId", resp.ZoneId)
+	return resp.ZoneId, nil
+}
+
+func (s *PrivateZoneClient) Delete(id string) error {
+	logger := s.WithValues("SDKAction", "Delete", "id", id)
+
+	req := pvtz.CreateDeleteZoneRequest()
+	req.Scheme = "https"
+	req.ZoneId = id
+
+	if err := retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting")
+		err := Call(s.limiter, "pvtz", "DeleteZone", func() error {
+			_, err := s.cli.DeleteZone(req)
+			return err
+		})
+		if err != nil {
+			if zoneNotFound(err) {
+				return nil
+			}
+			if retry.IsConflict(err) {
+				return retry.ErrRetry
+			}
+			logger.Info("error: " + err.Error())
+		}
+		return errors.Wrap(err, "DeleteZone")
+	}); err != nil {
+		return err
+	}
+
+	logger.Info("success")
+	return nil
+}
+
+// BindVpcs replaces the VPCs bound to the zone; all of them are in the region of the client.
+func (s *PrivateZoneClient) BindVpcs(id string, vpcIDs []string) error {
+	logger := s.WithValues("SDKAction", "BindVpcs", "id", id, "vpcs", vpcIDs)
+
+	req := pvtz.CreateBindZoneVpcRequest()
+	req.Scheme = "https"
+	req.ZoneId = id
+	vpcs := make([]pvtz.BindZoneVpcVpcs, 0, len(vpcIDs))
+	for _, vpcID := range vpcIDs {
+		vpcs = append(vpcs, pvtz.BindZoneVpcVpcs{RegionId: s.regionID, VpcId: vpcID})
+	}
+	req.Vpcs = &vpcs
+
+	return retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting")
+		err := Call(s.limiter, "pvtz", "BindZoneVpc", func() error {
+			_, err := s.cli.BindZoneVpc(req)
+			return err
+		})
+		if err != nil {
+			if retry.IsConflict(err) {
+				return retry.ErrRetry
+			}
+
+			logger.Info("error: " + err.Error())
+			return errors.Wrap(err, "BindZoneVpc")
+		}
+
+		logger.Info("success")
+		return nil
+	})
+}
+
+// DescribeRecord returns the record with the given host record and type, or nil if there is none.
+func (s *PrivateZoneClient) DescribeRecord(zoneID, rr, recordType string) (*pvtz.Record, error) {
+	logger := s.WithValues("SDKAction", "DescribeRecord", "zone", zoneID, "rr", rr)
+
+	req := pvtz.CreateDescribeZoneRecordsRequest()
+	req.Scheme = "https"
+	req.ZoneId = zoneID
+	req.Keyword = rr
+	req.SearchMode = "EXACT"
+	req.PageSize = requests.NewInteger(100)
+
+	var resp *pvtz.DescribeZoneRecordsResponse
+	if err := retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting")
+		err := Call(s.limiter, "pvtz", "DescribeZoneRecords", func() error {
+			var err error
+			resp, err = s.cli.DescribeZoneRecords(req)
+			return err
+		})
+		if err != nil {
+			logger.Info("error: " + err.Error())
+		}
+		return errors.Wrap(err, "DescribeZoneRecords")
+	}); err != nil {
+		if zoneNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	for i := range resp.Records.Record {
+		if r := &resp.Records.Record[i]; r.Rr == rr && r.Type == recordType {
+			logger.Info("success", "RecordId", r.RecordId)
+			return r, nil
+		}
+	}
+	logger.Info("not found")
+	return nil, nil
+}
+
+func (s *PrivateZoneClient) CreateRecord(zoneID, rr, recordType, value string, ttl int) (string, error) {
+	logger := s.WithValues("SDKAction", "CreateRecord", "zone", zoneID, "rr", rr, "value", value)
+
+	req := pvtz.CreateAddZoneRecordRequest()
+	req.Scheme = "https"
+	req.ZoneId = zoneID
+	req.Rr = rr
+	req.Type = recordType
+	req.Value = value
+	req.Ttl = requests.NewInteger(ttl)
+
+	var resp *pvtz.AddZoneRecordResponse
+	if err := retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting")
+		err := Call(s.limiter, "pvtz", "AddZoneRecord", func() error {
+			var err error
+			resp, err = s.cli.AddZoneRecord(req)
+			return err
+		})
+		if err != nil {
+			logger.Info("error: " + err.Error())
+		}
+		return errors.Wrap(err, "AddZoneRecord")
+	}); err != nil {
+		return "", err
+	}
+
+	id := strconv.Itoa(resp.RecordId)
+	logger.Info("success", "RecordId", id)
+	return id, nil
+}
+
+func (s *PrivateZoneClient) UpdateRecord(recordID, rr, recordType, value string, ttl int) error {
+	logger := s.WithValues("SDKAction", "UpdateRecord", "id", recordID, "value", value)
+
+	req := pvtz.CreateUpdateZoneRecordRequest()
+	req.Scheme = "https"
+	req.RecordId = requests.Integer(recordID)
+	req.Rr = rr
+	req.Type = recordType
+	req.Value = value
+	req.Ttl = requests.NewInteger(ttl)
+
+	if err := retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting")
+		err := Call(s.limiter, "pvtz", "UpdateZoneRecord", func() error {
+			_, err := s.cli.UpdateZoneRecord(req)
+			return err
+		})
+		if err != nil {
+			logger.Info("error: " + err.Error())
+		}
+		return errors.Wrap(err, "UpdateZoneRecord")
+	}); err != nil {
+		return err
+	}
+
+	logger.Info("success")
+	return nil
+}
+
+func (s *PrivateZoneClient) DeleteRecord(recordID string) error {
+	logger := s.WithValues("SDKAction", "DeleteRecord", "id", recordID)
+
+	req := pvtz.CreateDeleteZoneRecordRequest()
+	req.Scheme = "https"
+	req.RecordId = requests.Integer(recordID)
+
+	if err := retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting")
+		err := Call(s.limiter, "pvtz", "DeleteZoneRecord", func() error {
+			_, err := s.cli.DeleteZoneRecord(req)
+			return err
+		})
+		if err != nil {
+			if zoneNotFound(err) {
+				return nil
+			}
+			logger.Info("error: " + err.Error())
+		}
+		return errors.Wrap(err, "DeleteZoneRecord")
+	}); err != nil {
+		return err
+	}
+
+	logger.Info("success")
+	return nil
+}
@@ -0,0 +1,36 @@
+package aliyun
+
+import (
+	"strings"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+var (
+	// RateLimitQPS is the sustained rate of API calls allowed per account, region and service.
+	RateLimitQPS float64 = 10
+	// RateLimitBurst is the number of API calls that may momentarily exceed RateLimitQPS.
+	RateLimitBurst = 20
+)
+
+var (
+	limitersMu sync.Mutex
+	limiters   = map[string]*rate.Limiter{}
+)
+
+// Limiter returns the token bucket shared by every client calling the given service in regionID
+// with the configured account, so that all reconciles together stay within the account API quota.
+func Limiter(regionID, service string) *rate.Limiter {
+	key := strings.Join([]string{AccessKeyId, regionID, service}, "/")
+
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+
+	l, ok := limiters[key]
+	if !ok {
+		l = rate.NewLimiter(rate.Limit(RateLimitQPS), RateLimitBurst)
+		limiters[key] = l
+	}
+	return l
+}
@@ -0,0 +1,89 @@
+package aliyun
+
+import (
+	"sync"
+	"time"
+
+	sdkerr "github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
+	"sigs.k8s.io/cluster-api-provider-alicloud/pkg/aliyun/retry"
+)
+
+// credentialCheckTimeout bounds a credential check so that a hanging endpoint fails the probe instead of blocking it.
+const credentialCheckTimeout = 5 * time.Second
+
+// credentialCheckInterval is the minimum time between two credential checks. The probe has its own
+// limiter rather than the account one, so that reconcile load never fails it.
+const credentialCheckInterval = 10 * time.Second
+
+func NewRegionClient(logger logr.Logger, regionID string) (*RegionClient, error) {
+	cli, err := ecs.NewClientWithAccessKey(regionID, AccessKeyId, AccessKeySecret)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create region client")
+	}
+	cli.GetConfig().WithAutoRetry(false)
+	cli.SetConnectTimeout(credentialCheckTimeout)
+	cli.SetReadTimeout(credentialCheckTimeout)
+	return &RegionClient{
+		Logger:  logger.WithValues("client", "region"),
+		cli:     cli,
+		limiter: rate.NewLimiter(rate.Every(credentialCheckInterval), 1),
+	}, nil
+}
+
+type RegionClient struct {
+	logr.Logger
+	cli     *ecs.Client
+	limiter *rate.Limiter
+
+	mu      sync.Mutex
+	lastErr error
+}
+
+// CheckCredentials validates the configured AccessKey with a single DescribeRegions call. It is not retried,
+// and the returned error tells missing or rejected credentials apart from an unreachable endpoint.
+// A check refused by the probe limiter is skipped and returns the result of the previous one.
+func (s *RegionClient) CheckCredentials() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.limiter.Allow() {
+		s.V(1).Info("credential check skipped, checked too recently")
+		return s.lastErr
+	}
+	s.lastErr = s.checkCredentials()
+	return s.lastErr
+}
+
+func (s *RegionClient) checkCredentials() error {
+	logger := s.WithValues("SDKAction", "CheckCredentials")
+	if len(AccessKeyId) == 0 || len(AccessKeySecret) == 0 {
+		return errors.New("credentials not configured: ACCESS_KEY_ID and ACCESS_SECRET must be set")
+	}
+
+	req := ecs.CreateDescribeRegionsRequest()
+	req.Scheme = "https"
+
+	logger.V(1).Info("requesting")
+	err := observe("ecs", "DescribeRegions", func() error {
+		_, err := s.cli.DescribeRegions(req)
+		return err
+	})
+	if err == nil {
+		return nil
+	}
+
+	code, requestID, message := ErrorDetail(err)
+	_, fromServer := errors.Cause(err).(*sdkerr.ServerError)
+	switch {
+	case !fromServer || retry.IsTransient(err):
+		return errors.Wrap(err, "endpoint unreachable")
+	case retry.IsThrottled(err):
+		// the credentials were accepted, the account is just busy
+		logger.Info("throttled", "code", code, "requestID", requestID)
+		return nil
+	}
+	return errors.Errorf("credentials rejected: %s: %s (RequestId %s)", code, message, requestID)
+}
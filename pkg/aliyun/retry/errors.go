@@ -0,0 +1,167 @@
+package retry
+
+import (
+	"net"
+	"strings"
+
+	sdkerr "github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
+	"github.com/pkg/errors"
+)
+
+// Class is the category of an Alibaba Cloud SDK error, deciding how callers react to it.
+type Class int
+
+const (
+	// Unknown errors are treated as terminal.
+	Unknown Class = iota
+	// NotFound means the target resource does not exist.
+	NotFound
+	// Throttled means the call was rejected by flow control and should be retried with backoff.
+	Throttled
+	// Conflict means the resource is busy or still referenced by another resource,
+	// e.g. DependencyViolation or IncorrectStatus; it usually resolves itself after a while.
+	Conflict
+	// InvalidParameter means the request itself is wrong and will never succeed as is.
+	InvalidParameter
+	// QuotaExceeded means the account ran out of quota for the resource.
+	QuotaExceeded
+	// Transient covers timeouts, network failures and server side errors.
+	Transient
+)
+
+func (c Class) String() string {
+	switch c {
+	case NotFound:
+		return "NotFound"
+	case Throttled:
+		return "Throttled"
+	case Conflict:
+		return "Conflict"
+	case InvalidParameter:
+		return "InvalidParameter"
+	case QuotaExceeded:
+		return "QuotaExceeded"
+	case Transient:
+		return "Transient"
+	}
+	return "Unknown"
+}
+
+var (
+	// throttlingCodes are error code prefixes returned when the account or API quota is exceeded.
+	throttlingCodes = []string{
+		"Throttling",
+		"ServiceUnavailable",
+		"QPS.Limit",
+	}
+	// conflictCodes are error code fragments returned while a resource is in use or changing state.
+	conflictCodes = []string{
+		"Dependency",
+		"IncorrectStatus",
+		"IncorrectVpcStatus",
+		"IncorrectInstanceStatus",
+		"InvalidStatus",
+		"InvalidIpStatus",
+		"OperationConflict",
+		"TaskConflict",
+		"LastTokenProcessing",
+		"InstanceLocked",
+	}
+	quotaCodes = []string{
+		"QuotaExceed",
+		"Quota.Exceeded",
+		"QuotaExhausted",
+		"LimitExceeded",
+	}
+	transientCodes = []string{
+		sdkerr.TimeoutErrorCode,
+		"InternalError",
+		"UnknownError",
+		"ServiceTimeout",
+	}
+)
+
+// Code returns the Alibaba Cloud error code carried by err, or an empty string.
+func Code(err error) string {
+	if e, ok := errors.Cause(err).(sdkerr.Error); ok {
+		return e.ErrorCode()
+	}
+	return ""
+}
+
+// Classify unwraps err and returns its Class.
+func Classify(err error) Class {
+	if err == nil {
+		return Unknown
+	}
+
+	cause := errors.Cause(err)
+	if e, ok := cause.(net.Error); ok && (e.Timeout() || e.Temporary()) {
+		return Transient
+	}
+
+	e, ok := cause.(sdkerr.Error)
+	if !ok {
+		return Unknown
+	}
+	code := e.ErrorCode()
+
+	switch {
+	case e.HttpStatus() == 429 || hasPrefix(code, throttlingCodes):
+		return Throttled
+	case e.HttpStatus() == 404 || strings.Contains(code, "NotFound") || strings.Contains(code, "NotExist"):
+		return NotFound
+	case contains(code, quotaCodes):
+		return QuotaExceeded
+	case contains(code, conflictCodes):
+		return Conflict
+	case hasPrefix(code, transientCodes) || e.HttpStatus() >= 500:
+		return Transient
+	case strings.HasPrefix(code, "Invalid") || strings.HasPrefix(code, "Missing") ||
+		strings.HasPrefix(code, "Forbidden") || code == sdkerr.InvalidParamErrorCode || code == sdkerr.MissingParamErrorCode:
+		return InvalidParameter
+	}
+	return Unknown
+}
+
+// IsNotFound reports whether err means the target resource does not exist.
+func IsNotFound(err error) bool { return Classify(err) == NotFound }
+
+// IsThrottled reports whether err was caused by Alibaba Cloud rejecting the call due to flow control.
+func IsThrottled(err error) bool { return Classify(err) == Throttled }
+
+// IsConflict reports whether err means the resource is busy or still has dependencies.
+func IsConflict(err error) bool { return Classify(err) == Conflict }
+
+// IsInvalidParameter reports whether err was caused by a malformed request.
+func IsInvalidParameter(err error) bool { return Classify(err) == InvalidParameter }
+
+// IsQuotaExceeded reports whether err means the account quota for a resource is exhausted.
+func IsQuotaExceeded(err error) bool { return Classify(err) == QuotaExceeded }
+
+// IsTransient reports whether err is a timeout, network or server side failure.
+func IsTransient(err error) bool { return Classify(err) == Transient }
+
+// IsRetriable reports whether err is worth retrying with backoff.
+func IsRetriable(err error) bool {
+	c := Classify(err)
+	return c == Throttled || c == Transient
+}
+
+func hasPrefix(code string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(code, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(code string, fragments []string) bool {
+	for _, f := range fragments {
+		if strings.Contains(code, f) {
+			return true
+		}
+	}
+	return false
+}
@@ -3,7 +3,6 @@ package retry
 import (
 	"time"
 
-	sdkerr "github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/util/wait"
 )
@@ -12,35 +11,33 @@ var DefaultBackOf = wait.Backoff{
 	Duration: time.Second,
 	Factor:   2,
 	Steps:    32,
-	Jitter:   4,
+	Jitter:   0.1,
 	Cap:      20 * time.Second,
 }
 
 var ErrRetry = errors.New("retry")
 
+// Try calls fn until it succeeds, returns a terminal error or backoff is exhausted.
+// fn asks for another attempt by returning ErrRetry; throttled and transient SDK errors
+// are retried as well. Every other error, including NotFound, is returned to the caller.
 func Try(backoff wait.Backoff, fn func() error) error {
-	return wait.ExponentialBackoff(backoff, func() (bool, error) {
+	var lastErr error
+	err := wait.ExponentialBackoff(backoff, func() (bool, error) {
 		err := fn()
 		if err == nil {
 			return true, nil
 		}
 
-		if errors.Cause(err) == ErrRetry {
+		if errors.Cause(err) == ErrRetry || IsRetriable(err) {
+			lastErr = err
 			return false, nil
 		}
 
-		if err, ok := err.(sdkerr.Error); ok {
-			// timeout or server errors should retry
-			if err.ErrorCode() == sdkerr.TimeoutErrorCode || err.HttpStatus() >= 500 {
-				return false, nil
-			}
-			// ignore not found error
-			if err.HttpStatus() == 404 {
-				return true, nil
-			}
-		}
-
 		// errors can't retry
 		return false, err
 	})
+	if err == wait.ErrWaitTimeout && lastErr != nil && errors.Cause(lastErr) != ErrRetry {
+		return errors.Wrap(lastErr, "retries exhausted")
+	}
+	return err
 }
@@ -0,0 +1,355 @@
+package aliyun
+
+import (
+	"fmt"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
+	infrav1 "sigs.k8s.io/cluster-api-provider-alicloud/api/v1alpha2"
+	"sigs.k8s.io/cluster-api-provider-alicloud/pkg/aliyun/retry"
+)
+
+const (
+	RouteTableTypeSystem = "System"
+	RouteTableTypeCustom = "Custom"
+)
+
+func NewRouteTableClient(logger logr.Logger, regionID string) (*RouteTableClient, error) {
+	cli, err := vpc.NewClientWithAccessKey(regionID, AccessKeyId, AccessKeySecret)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create routeTable client")
+	}
+	return &RouteTableClient{
+		Logger:  logger.WithValues("client", "routeTable"),
+		cli:     cli,
+		limiter: Limiter(regionID, "vpc"),
+	}, nil
+}
+
+type RouteTableClient struct {
+	logr.Logger
+	cli     *vpc.Client
+	limiter *rate.Limiter
+}
+
+func (s *RouteTableClient) describe(req *vpc.DescribeRouteTableListRequest) (*vpc.RouterTableListType, error) {
+	logger := s.WithValues("SDKAction", "DescribeRouteTableList", "id", req.RouteTableId, "router", req.RouterId)
+
+	var resp *vpc.DescribeRouteTableListResponse
+	if err := retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting")
+		err := Call(s.limiter, "vpc", "DescribeRouteTableList", func() error {
+			var err error
+			resp, err = s.cli.DescribeRouteTableList(req)
+			return err
+		})
+		if err != nil {
+			logger.Info("error: " + err.Error())
+		}
+		return errors.Wrap(err, "DescribeRouteTableList")
+	}); err != nil {
+		if retry.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	logger.Info("success", "TotalCount", resp.TotalCount)
+	for i := range resp.RouterTableList.RouterTableListType {
+		table := &resp.RouterTableList.RouterTableListType[i]
+		if len(req.RouteTableId) > 0 || table.RouteTableType == RouteTableTypeSystem {
+			return table, nil
+		}
+	}
+	return nil, nil
+}
+
+// Describe returns the route table with the given ID, or nil if it does not exist.
+func (s *RouteTableClient) Describe(id string) (*vpc.RouterTableListType, error) {
+	req := vpc.CreateDescribeRouteTableListRequest()
+	req.Scheme = "https"
+	req.RouteTableId = id
+	return s.describe(req)
+}
+
+// DescribeSystem returns the system route table of a VPC router.
+func (s *RouteTableClient) DescribeSystem(vrouterID string) (*vpc.RouterTableListType, error) {
+	req := vpc.CreateDescribeRouteTableListRequest()
+	req.Scheme = "https"
+	req.RouterType = "VRouter"
+	req.RouterId = vrouterID
+	req.PageSize = requests.NewInteger(50)
+	return s.describe(req)
+}
+
+func (s *RouteTableClient) Create(spec infrav1.RouteTableSpec, vpcID string) (string, error) {
+	logger := s.WithValues("SDKAction", "Create")
+
+	req := spec.ConvertToCreateReq(vpcID)
+	var resp *vpc.CreateRouteTableResponse
+	if err := retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting", "request", req)
+		err := Call(s.limiter, "vpc", "CreateRouteTable", func() error {
+			var err error
+			resp, err = s.cli.CreateRouteTable(req)
+			return err
+		})
+		if err != nil {
+			if retry.IsConflict(err) {
+				return retry.ErrRetry
+			}
+			logger.Info("error: " + err.Error())
+		}
+		return errors.Wrap(err, "CreateRouteTable")
+	}); err != nil {
+		return "", err
+	}
+
+	logger.Info("success", "RouteTableId", resp.RouteTableId)
+	return resp.RouteTableId, nil
+}
+
+func (s *RouteTableClient) WaitReady(id string) (*vpc.RouterTableListType, error) {
+	logger := s.WithValues("SDKAction", "WaitReady", "id", id)
+
+	var ret *vpc.RouterTableListType
+	if err := retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("describing")
+		var err error
+		ret, err = s.Describe(id)
+		if err != nil {
+			logger.Info("error: " + err.Error())
+			return errors.Wrap(err, "Describe")
+		}
+		if ret == nil {
+			logger.Info("nil result")
+			return retry.ErrRetry
+		}
+		if ret.Status != infrav1.Available {
+			logger.Info(fmt.Sprintf("waiting for status: %v, now status: %v", infrav1.Available, ret.Status))
+			return retry.ErrRetry
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	logger.Info("ready")
+	return ret, nil
+}
+
+func (s *RouteTableClient) Delete(id string) error {
+	logger := s.WithValues("SDKAction", "Delete", "id", id)
+
+	req := vpc.CreateDeleteRouteTableRequest()
+	req.Scheme = "https"
+	req.RouteTableId = id
+
+	if err := retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting")
+		err := Call(s.limiter, "vpc", "DeleteRouteTable", func() error {
+			_, err := s.cli.DeleteRouteTable(req)
+			return err
+		})
+		if err != nil {
+			if retry.IsNotFound(err) {
+				return nil
+			}
+			if retry.IsConflict(err) {
+				return retry.ErrRetry
+			}
+			logger.Info("error: " + err.Error())
+		}
+		return errors.Wrap(err, "DeleteRouteTable")
+	}); err != nil {
+		return err
+	}
+
+	logger.Info("success")
+	return nil
+}
+
+func (s *RouteTableClient) Associate(id, vswitchID string) error {
+	logger := s.WithValues("SDKAction", "Associate", "id", id, "vswitch", vswitchID)
+
+	req := vpc.CreateAssociateRouteTableRequest()
+	req.Scheme = "https"
+	req.RouteTableId = id
+	req.VSwitchId = vswitchID
+
+	return retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting")
+		err := Call(s.limiter, "vpc", "AssociateRouteTable", func() error {
+			_, err := s.cli.AssociateRouteTable(req)
+			return err
+		})
+		if err != nil {
+			if retry.IsConflict(err) {
+				return retry.ErrRetry
+			}
+
+			logger.Info("error: " + err.Error())
+			return errors.Wrap(err, "AssociateRouteTable")
+		}
+
+		logger.Info("success")
+		return nil
+	})
+}
+
+func (s *RouteTableClient) Unassociate(id, vswitchID string) error {
+	logger := s.WithValues("SDKAction", "Unassociate", "id", id, "vswitch", vswitchID)
+
+	req := vpc.CreateUnassociateRouteTableRequest()
+	req.Scheme = "https"
+	req.RouteTableId = id
+	req.VSwitchId = vswitchID
+
+	return retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting")
+		err := Call(s.limiter, "vpc", "UnassociateRouteTable", func() error {
+			_, err := s.cli.UnassociateRouteTable(req)
+			return err
+		})
+		if err != nil {
+			if retry.IsNotFound(err) {
+				return nil
+			}
+			if retry.IsConflict(err) {
+				return retry.ErrRetry
+			}
+
+			logger.Info("error: " + err.Error())
+			return errors.Wrap(err, "UnassociateRouteTable")
+		}
+
+		logger.Info("success")
+		return nil
+	})
+}
+
+// DescribeEntries returns the custom route entries of a table, or only the one for cidr when it is set.
+func (s *RouteTableClient) DescribeEntries(id, cidr string) ([]infrav1.RouteEntry, error) {
+	logger := s.WithValues("SDKAction", "DescribeEntries", "id", id, "cidr", cidr)
+
+	req := vpc.CreateDescribeRouteEntryListRequest()
+	req.Scheme = "https"
+	req.RouteTableId = id
+	req.RouteEntryType = RouteTableTypeCustom
+	req.DestinationCidrBlock = cidr
+	req.MaxResult = requests.NewInteger(100)
+
+	var ret []infrav1.RouteEntry
+	for {
+		var resp *vpc.DescribeRouteEntryListResponse
+		if err := retry.Try(retry.DefaultBackOf, func() error {
+			logger.Info("requesting", "NextToken", req.NextToken)
+			err := Call(s.limiter, "vpc", "DescribeRouteEntryList", func() error {
+				var err error
+				resp, err = s.cli.DescribeRouteEntryList(req)
+				return err
+			})
+			if err != nil {
+				logger.Info("error: " + err.Error())
+			}
+			return errors.Wrap(err, "DescribeRouteEntryList")
+		}); err != nil {
+			return nil, err
+		}
+
+		for i := range resp.RouteEntrys.RouteEntry {
+			var e infrav1.RouteEntry
+			e.FillFrom(&resp.RouteEntrys.RouteEntry[i])
+			ret = append(ret, e)
+		}
+		if len(resp.NextToken) == 0 {
+			break
+		}
+		req.NextToken = resp.NextToken
+	}
+
+	logger.Info("success", "count", len(ret))
+	return ret, nil
+}
+
+// CreateEntry routes cidr to an ECS instance. An existing route to the same instance is accepted.
+func (s *RouteTableClient) CreateEntry(id, cidr, instanceID, name string) error {
+	logger := s.WithValues("SDKAction", "CreateEntry", "id", id, "cidr", cidr, "instance", instanceID)
+
+	req := vpc.CreateCreateRouteEntryRequest()
+	req.Scheme = "https"
+	req.RouteTableId = id
+	req.DestinationCidrBlock = cidr
+	req.NextHopType = "Instance"
+	req.NextHopId = instanceID
+	req.RouteEntryName = name
+
+	if err := retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting")
+		req.ClientToken = ""
+		err := Call(s.limiter, "vpc", "CreateRouteEntry", func() error {
+			_, err := s.cli.CreateRouteEntry(req)
+			return err
+		})
+		if err != nil {
+			if retry.Code(err) == "InvalidCIDRBlock.Duplicate" {
+				entries, derr := s.DescribeEntries(id, cidr)
+				if derr != nil {
+					return derr
+				}
+				for _, e := range entries {
+					if e.NextHopId == instanceID {
+						return nil
+					}
+				}
+				return errors.Wrapf(err, "route for %s exists with another next hop", cidr)
+			}
+			if retry.IsConflict(err) {
+				return retry.ErrRetry
+			}
+			logger.Info("error: " + err.Error())
+		}
+		return errors.Wrap(err, "CreateRouteEntry")
+	}); err != nil {
+		return err
+	}
+
+	logger.Info("success")
+	return nil
+}
+
+func (s *RouteTableClient) DeleteEntry(id, cidr, instanceID string) error {
+	logger := s.WithValues("SDKAction", "DeleteEntry", "id", id, "cidr", cidr, "instance", instanceID)
+
+	req := vpc.CreateDeleteRouteEntryRequest()
+	req.Scheme = "https"
+	req.RouteTableId = id
+	req.DestinationCidrBlock = cidr
+	req.NextHopId = instanceID
+
+	if err := retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting")
+		err := Call(s.limiter, "vpc", "DeleteRouteEntry", func() error {
+			_, err := s.cli.DeleteRouteEntry(req)
+			return err
+		})
+		if err != nil {
+			if retry.IsNotFound(err) {
+				return nil
+			}
+			if retry.IsConflict(err) {
+				return retry.ErrRetry
+			}
+			logger.Info("error: " + err.Error())
+		}
+		return errors.Wrap(err, "DeleteRouteEntry")
+	}); err != nil {
+		return err
+	}
+
+	logger.Info("success")
+	return nil
+}
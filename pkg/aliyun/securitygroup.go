@@ -1,12 +1,11 @@
 package aliyun
 
 import (
-	"strings"
-
-	sdkerr "github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
 	infrav1 "sigs.k8s.io/cluster-api-provider-alicloud/api/v1alpha2"
 	"sigs.k8s.io/cluster-api-provider-alicloud/pkg/aliyun/retry"
 )
@@ -17,14 +16,16 @@ func NewSecurityGroupClient(logger logr.Logger, regionID string) (*SecurityGroup
 		return nil, errors.Wrap(err, "failed to create securityGroup client")
 	}
 	return &SecurityGroupClient{
-		Logger: logger.WithValues("client", "securityGroup"),
-		cli:    cli,
+		Logger:  logger.WithValues("client", "securityGroup"),
+		cli:     cli,
+		limiter: Limiter(regionID, "ecs"),
 	}, nil
 }
 
 type SecurityGroupClient struct {
 	logr.Logger
-	cli *ecs.Client
+	cli     *ecs.Client
+	limiter *rate.Limiter
 }
 
 func (s *SecurityGroupClient) Describe(id string) (*infrav1.SecurityGroup, error) {
@@ -37,13 +38,19 @@ func (s *SecurityGroupClient) Describe(id string) (*infrav1.SecurityGroup, error
 	var resp *ecs.DescribeSecurityGroupsResponse
 	if err := retry.Try(retry.DefaultBackOf, func() error {
 		logger.Info("requesting")
-		var err error
-		resp, err = s.cli.DescribeSecurityGroups(req)
+		err := Call(s.limiter, "ecs", "DescribeSecurityGroups", func() error {
+			var err error
+			resp, err = s.cli.DescribeSecurityGroups(req)
+			return err
+		})
 		if err != nil {
 			logger.Info("error: " + err.Error())
 		}
 		return errors.Wrap(err, "DescribeSecurityGroups")
 	}); err != nil {
+		if retry.IsNotFound(err) {
+			return nil, nil
+		}
 		return nil, err
 	}
 
@@ -64,8 +71,11 @@ func (s *SecurityGroupClient) Create(spec infrav1.SecurityGroupSpec, vpcId strin
 	var resp *ecs.CreateSecurityGroupResponse
 	if err := retry.Try(retry.DefaultBackOf, func() error {
 		logger.Info("requesting", "request", req)
-		var err error
-		resp, err = s.cli.CreateSecurityGroup(req)
+		err := Call(s.limiter, "ecs", "CreateSecurityGroup", func() error {
+			var err error
+			resp, err = s.cli.CreateSecurityGroup(req)
+			return err
+		})
 		if err != nil {
 			logger.Info("error: " + err.Error())
 		}
@@ -75,23 +85,94 @@ func (s *SecurityGroupClient) Create(spec infrav1.SecurityGroupSpec, vpcId strin
 	}
 
 	logger.Info("success", "SecurityGroupId", resp.SecurityGroupId)
+	return resp.SecurityGroupId, nil
+}
 
-	rules := spec.ConvertToAuthorizeSecurityGroupRequest(resp.SecurityGroupId)
-	for _, rule := range rules {
-		logger := s.WithValues("SDKAction", "CreateRule")
-		if err := retry.Try(retry.DefaultBackOf, func() error {
-			logger.Info("requesting", "request", req)
-			_, err := s.cli.AuthorizeSecurityGroup(rule)
-			if err != nil {
-				logger.Info("error: " + err.Error())
-			}
-			return errors.Wrap(err, "AuthorizeSecurityGroup")
-		}); err != nil {
-			return "", err
+// DescribeRules returns the ingress and egress rules of the security group.
+func (s *SecurityGroupClient) DescribeRules(id string) ([]infrav1.SecurityGroupRuleSpec, error) {
+	logger := s.WithValues("SDKAction", "DescribeRules", "id", id)
+
+	req := ecs.CreateDescribeSecurityGroupAttributeRequest()
+	req.Scheme = "https"
+	req.SecurityGroupId = id
+	req.Direction = "all"
+
+	var resp *ecs.DescribeSecurityGroupAttributeResponse
+	if err := retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting")
+		err := Call(s.limiter, "ecs", "DescribeSecurityGroupAttribute", func() error {
+			var err error
+			resp, err = s.cli.DescribeSecurityGroupAttribute(req)
+			return err
+		})
+		if err != nil {
+			logger.Info("error: " + err.Error())
 		}
+		return errors.Wrap(err, "DescribeSecurityGroupAttribute")
+	}); err != nil {
+		return nil, err
 	}
 
-	return resp.SecurityGroupId, nil
+	var list []infrav1.SecurityGroupRuleSpec
+	for i := range resp.Permissions.Permission {
+		list = append(list, infrav1.SecurityGroupRuleFromPermission(&resp.Permissions.Permission[i]))
+	}
+
+	logger.Info("success", "TotalCount", len(list))
+	return list, nil
+}
+
+// AuthorizeRule adds rule to the security group.
+func (s *SecurityGroupClient) AuthorizeRule(id string, rule *infrav1.SecurityGroupRuleSpec) error {
+	logger := s.WithValues("SDKAction", "AuthorizeRule", "id", id, "rule", rule.Key())
+
+	return retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting")
+		var err error
+		if rule.IsEgress() {
+			err = Call(s.limiter, "ecs", "AuthorizeSecurityGroupEgress", func() error {
+				_, err := s.cli.AuthorizeSecurityGroupEgress(rule.ConvertToAuthorizeEgressReq(id))
+				return err
+			})
+		} else {
+			err = Call(s.limiter, "ecs", "AuthorizeSecurityGroup", func() error {
+				_, err := s.cli.AuthorizeSecurityGroup(rule.ConvertToAuthorizeReq(id))
+				return err
+			})
+		}
+		if err != nil {
+			logger.Info("error: " + err.Error())
+		}
+		return errors.Wrap(err, "AuthorizeSecurityGroup")
+	})
+}
+
+// RevokeRule removes rule from the security group.
+func (s *SecurityGroupClient) RevokeRule(id string, rule *infrav1.SecurityGroupRuleSpec) error {
+	logger := s.WithValues("SDKAction", "RevokeRule", "id", id, "rule", rule.Key())
+
+	return retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting")
+		var err error
+		if rule.IsEgress() {
+			err = Call(s.limiter, "ecs", "RevokeSecurityGroupEgress", func() error {
+				_, err := s.cli.RevokeSecurityGroupEgress(rule.ConvertToRevokeEgressReq(id))
+				return err
+			})
+		} else {
+			err = Call(s.limiter, "ecs", "RevokeSecurityGroup", func() error {
+				_, err := s.cli.RevokeSecurityGroup(rule.ConvertToRevokeReq(id))
+				return err
+			})
+		}
+		if err != nil {
+			if retry.IsNotFound(err) {
+				return nil
+			}
+			logger.Info("error: " + err.Error())
+		}
+		return errors.Wrap(err, "RevokeSecurityGroup")
+	})
 }
 
 func (s *SecurityGroupClient) WaitReady(id string) (*infrav1.SecurityGroup, error) {
@@ -130,10 +211,15 @@ func (s *SecurityGroupClient) Delete(id string) error {
 
 	if err := retry.Try(retry.DefaultBackOf, func() error {
 		logger.Info("requesting", "request", req)
-		var err error
-		_, err = s.cli.DeleteSecurityGroup(req)
+		err := Call(s.limiter, "ecs", "DeleteSecurityGroup", func() error {
+			_, err := s.cli.DeleteSecurityGroup(req)
+			return err
+		})
 		if err != nil {
-			if e, ok := err.(sdkerr.Error); ok && strings.Contains(e.ErrorCode(), "Dependency") {
+			if retry.IsNotFound(err) {
+				return nil
+			}
+			if retry.IsConflict(err) {
 				return retry.ErrRetry
 			}
 			logger.Info("error: " + err.Error())
@@ -146,3 +232,49 @@ func (s *SecurityGroupClient) Delete(id string) error {
 	logger.Info("success")
 	return nil
 }
+
+// DescribeByTags returns the IDs of the security groups in vpcID carrying all of tags.
+func (s *SecurityGroupClient) DescribeByTags(vpcID string, tags map[string]string) ([]string, error) {
+	logger := s.WithValues("SDKAction", "DescribeByTags", "vpc", vpcID, "tags", tags)
+
+	req := ecs.CreateDescribeSecurityGroupsRequest()
+	req.Scheme = "https"
+	req.VpcId = vpcID
+	req.PageSize = requests.NewInteger(50)
+	var reqTags []ecs.DescribeSecurityGroupsTag
+	for k, v := range tags {
+		reqTags = append(reqTags, ecs.DescribeSecurityGroupsTag{Key: k, Value: v})
+	}
+	req.Tag = &reqTags
+
+	var ids []string
+	for page := 1; ; page++ {
+		req.PageNumber = requests.NewInteger(page)
+
+		var resp *ecs.DescribeSecurityGroupsResponse
+		if err := retry.Try(retry.DefaultBackOf, func() error {
+			logger.Info("requesting", "page", page)
+			err := Call(s.limiter, "ecs", "DescribeSecurityGroups", func() error {
+				var err error
+				resp, err = s.cli.DescribeSecurityGroups(req)
+				return err
+			})
+			if err != nil {
+				logger.Info("error: " + err.Error())
+			}
+			return errors.Wrap(err, "DescribeSecurityGroups")
+		}); err != nil {
+			return nil, err
+		}
+
+		for _, sg := range resp.SecurityGroups.SecurityGroup {
+			ids = append(ids, sg.SecurityGroupId)
+		}
+		if len(ids) >= resp.TotalCount || len(resp.SecurityGroups.SecurityGroup) == 0 {
+			break
+		}
+	}
+
+	logger.Info("success", "ids", ids)
+	return ids, nil
+}
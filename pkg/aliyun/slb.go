@@ -2,15 +2,12 @@ package aliyun
 
 import (
 	"fmt"
-	"strings"
-
-	sdkerr "github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
 
 	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
-
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/slb"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
 	infrav1 "sigs.k8s.io/cluster-api-provider-alicloud/api/v1alpha2"
 	"sigs.k8s.io/cluster-api-provider-alicloud/pkg/aliyun/retry"
 )
@@ -21,14 +18,16 @@ func NewSLBClient(logger logr.Logger, regionID string) (*SLBClient, error) {
 		return nil, errors.Wrap(err, "failed to create slb client")
 	}
 	return &SLBClient{
-		Logger: logger.WithValues("client", "slb"),
-		cli:    cli,
+		Logger:  logger.WithValues("client", "slb"),
+		cli:     cli,
+		limiter: Limiter(regionID, "slb"),
 	}, nil
 }
 
 type SLBClient struct {
 	logr.Logger
-	cli *slb.Client
+	cli     *slb.Client
+	limiter *rate.Limiter
 }
 
 func (s *SLBClient) Describe(id string) (*infrav1.SLB, error) {
@@ -41,13 +40,19 @@ func (s *SLBClient) Describe(id string) (*infrav1.SLB, error) {
 	var resp *slb.DescribeLoadBalancersResponse
 	if err := retry.Try(retry.DefaultBackOf, func() error {
 		logger.Info("requesting")
-		var err error
-		resp, err = s.cli.DescribeLoadBalancers(req)
+		err := Call(s.limiter, "slb", "DescribeLoadBalancers", func() error {
+			var err error
+			resp, err = s.cli.DescribeLoadBalancers(req)
+			return err
+		})
 		if err != nil {
 			logger.Info("error: " + err.Error())
 		}
 		return errors.Wrap(err, "DescribeLoadBalancers")
 	}); err != nil {
+		if retry.IsNotFound(err) {
+			return nil, nil
+		}
 		return nil, err
 	}
 
@@ -68,8 +73,11 @@ func (s *SLBClient) Create(spec infrav1.SLBSpec, vpcId string) (string, error) {
 	var resp *slb.CreateLoadBalancerResponse
 	if err := retry.Try(retry.DefaultBackOf, func() error {
 		logger.Info("requesting", "request", req)
-		var err error
-		resp, err = s.cli.CreateLoadBalancer(req)
+		err := Call(s.limiter, "slb", "CreateLoadBalancer", func() error {
+			var err error
+			resp, err = s.cli.CreateLoadBalancer(req)
+			return err
+		})
 		if err != nil {
 			logger.Info("error: " + err.Error())
 		}
@@ -123,10 +131,15 @@ func (s *SLBClient) Delete(id string) error {
 
 	if err := retry.Try(retry.DefaultBackOf, func() error {
 		logger.Info("requesting", "request", req)
-		var err error
-		_, err = s.cli.DeleteLoadBalancer(req)
+		err := Call(s.limiter, "slb", "DeleteLoadBalancer", func() error {
+			_, err := s.cli.DeleteLoadBalancer(req)
+			return err
+		})
 		if err != nil {
-			if e, ok := err.(sdkerr.Error); ok && strings.Contains(e.ErrorCode(), "Dependency") {
+			if retry.IsNotFound(err) {
+				return nil
+			}
+			if retry.IsConflict(err) {
 				return retry.ErrRetry
 			}
 			logger.Info("error: " + err.Error())
@@ -148,8 +161,11 @@ func (s *SLBClient) DescribeServerGroup(slbID string) ([]string, error) {
 	var vgResp *slb.DescribeVServerGroupsResponse
 	if err := retry.Try(retry.DefaultBackOf, func() error {
 		logger.Info("requesting", "request", req)
-		var err error
-		vgResp, err = s.cli.DescribeVServerGroups(req)
+		err := Call(s.limiter, "slb", "DescribeVServerGroups", func() error {
+			var err error
+			vgResp, err = s.cli.DescribeVServerGroups(req)
+			return err
+		})
 		if err != nil {
 			logger.Info("error: " + err.Error())
 		}
@@ -173,8 +189,11 @@ func (s *SLBClient) CreateServerGroup(spec infrav1.SLBSpec, slbID string) (strin
 	var resp *slb.CreateVServerGroupResponse
 	if err := retry.Try(retry.DefaultBackOf, func() error {
 		logger.Info("requesting", "request", req)
-		var err error
-		resp, err = s.cli.CreateVServerGroup(req)
+		err := Call(s.limiter, "slb", "CreateVServerGroup", func() error {
+			var err error
+			resp, err = s.cli.CreateVServerGroup(req)
+			return err
+		})
 		if err != nil {
 			logger.Info("error: " + err.Error())
 		}
@@ -193,8 +212,10 @@ func (s *SLBClient) CreateTCPListener(spec infrav1.SLBSpec, slbID string, vgID s
 	req := spec.ConvertToCreateSLBTCPListenerReq(slbID, vgID)
 	if err := retry.Try(retry.DefaultBackOf, func() error {
 		logger.Info("requesting", "request", req)
-		var err error
-		_, err = s.cli.CreateLoadBalancerTCPListener(req)
+		err := Call(s.limiter, "slb", "CreateLoadBalancerTCPListener", func() error {
+			_, err := s.cli.CreateLoadBalancerTCPListener(req)
+			return err
+		})
 		if err != nil {
 			logger.Info("error: " + err.Error())
 		}
@@ -207,20 +228,48 @@ func (s *SLBClient) CreateTCPListener(spec infrav1.SLBSpec, slbID string, vgID s
 	return nil
 }
 
-func (s *SLBClient) StartListener(slbID string) error {
-	logger := s.WithValues("SDKAction", "StartListener")
+// DescribeListenerStatus returns the status of the apiserver TCP listener of slbID,
+// or an empty string if the listener does not exist.
+func (s *SLBClient) DescribeListenerStatus(slbID string) (string, error) {
+	logger := s.WithValues("SDKAction", "DescribeListenerStatus", "id", slbID)
 
 	req := slb.CreateDescribeLoadBalancerTCPListenerAttributeRequest()
 	req.Scheme = "https"
 	req.LoadBalancerId = slbID
 	req.ListenerPort = requests.NewInteger(6443)
 
-	resp, err := s.cli.DescribeLoadBalancerTCPListenerAttribute(req)
+	var resp *slb.DescribeLoadBalancerTCPListenerAttributeResponse
+	if err := retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting")
+		err := Call(s.limiter, "slb", "DescribeLoadBalancerTCPListenerAttribute", func() error {
+			var err error
+			resp, err = s.cli.DescribeLoadBalancerTCPListenerAttribute(req)
+			return err
+		})
+		if err != nil {
+			logger.Info("error: " + err.Error())
+		}
+		return errors.Wrap(err, "DescribeLoadBalancerTCPListenerAttribute")
+	}); err != nil {
+		if retry.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	logger.Info("success", "Status", resp.Status)
+	return resp.Status, nil
+}
+
+func (s *SLBClient) StartListener(slbID string) error {
+	logger := s.WithValues("SDKAction", "StartListener")
+
+	status, err := s.DescribeListenerStatus(slbID)
 	if err != nil {
 		return err
 	}
 
-	if resp.Status != "starting" && resp.Status != "running" {
+	if status != "starting" && status != "running" {
 		startReq := slb.CreateStartLoadBalancerListenerRequest()
 		startReq.Scheme = "https"
 
@@ -229,7 +278,10 @@ func (s *SLBClient) StartListener(slbID string) error {
 
 		if err := retry.Try(retry.DefaultBackOf, func() error {
 			logger.Info("requesting", "request", startReq)
-			_, err := s.cli.StartLoadBalancerListener(startReq)
+			err := Call(s.limiter, "slb", "StartLoadBalancerListener", func() error {
+				_, err := s.cli.StartLoadBalancerListener(startReq)
+				return err
+			})
 			if err != nil {
 				logger.Info("error: " + err.Error())
 			}
@@ -243,6 +295,70 @@ func (s *SLBClient) StartListener(slbID string) error {
 	return nil
 }
 
+// DeleteListener deletes the apiserver TCP listener of slbID.
+func (s *SLBClient) DeleteListener(slbID string) error {
+	logger := s.WithValues("SDKAction", "DeleteListener", "id", slbID)
+
+	req := slb.CreateDeleteLoadBalancerListenerRequest()
+	req.Scheme = "https"
+	req.LoadBalancerId = slbID
+	req.ListenerPort = requests.NewInteger(6443)
+
+	if err := retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting")
+		err := Call(s.limiter, "slb", "DeleteLoadBalancerListener", func() error {
+			_, err := s.cli.DeleteLoadBalancerListener(req)
+			return err
+		})
+		if err != nil {
+			if retry.IsNotFound(err) {
+				return nil
+			}
+			if retry.IsConflict(err) {
+				return retry.ErrRetry
+			}
+			logger.Info("error: " + err.Error())
+		}
+		return errors.Wrap(err, "DeleteLoadBalancerListener")
+	}); err != nil {
+		return err
+	}
+
+	logger.Info("success")
+	return nil
+}
+
+func (s *SLBClient) DeleteServerGroup(vgID string) error {
+	logger := s.WithValues("SDKAction", "DeleteServerGroup", "id", vgID)
+
+	req := slb.CreateDeleteVServerGroupRequest()
+	req.Scheme = "https"
+	req.VServerGroupId = vgID
+
+	if err := retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting")
+		err := Call(s.limiter, "slb", "DeleteVServerGroup", func() error {
+			_, err := s.cli.DeleteVServerGroup(req)
+			return err
+		})
+		if err != nil {
+			if retry.IsNotFound(err) {
+				return nil
+			}
+			if retry.IsConflict(err) {
+				return retry.ErrRetry
+			}
+			logger.Info("error: " + err.Error())
+		}
+		return errors.Wrap(err, "DeleteVServerGroup")
+	}); err != nil {
+		return err
+	}
+
+	logger.Info("success")
+	return nil
+}
+
 func (s *SLBClient) VGAddBackendServers(vgID, instanceID, port, desc string) (*slb.AddVServerGroupBackendServersResponse, error) {
 	logger := s.WithValues("SDKAction", "VGAddBackendServers")
 
@@ -254,8 +370,11 @@ func (s *SLBClient) VGAddBackendServers(vgID, instanceID, port, desc string) (*s
 	var resp *slb.AddVServerGroupBackendServersResponse
 	if err := retry.Try(retry.DefaultBackOf, func() error {
 		logger.Info("requesting", "request", req)
-		var err error
-		resp, err = s.cli.AddVServerGroupBackendServers(req)
+		err := Call(s.limiter, "slb", "AddVServerGroupBackendServers", func() error {
+			var err error
+			resp, err = s.cli.AddVServerGroupBackendServers(req)
+			return err
+		})
 		if err != nil {
 			logger.Info("error: " + err.Error())
 		}
@@ -4,28 +4,39 @@ import (
 	"fmt"
 	"strings"
 
-	sdkerr "github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
 	infrav1 "sigs.k8s.io/cluster-api-provider-alicloud/api/v1alpha2"
 	"sigs.k8s.io/cluster-api-provider-alicloud/pkg/aliyun/retry"
 )
 
+var errSnatEntryExists = errors.New("snat entry exists")
+
+// Instance types an EIP can be associated with besides a NAT gateway.
+const (
+	EipInstanceTypeEcs = "EcsInstance"
+	EipInstanceTypeSlb = "SlbInstance"
+)
+
 func NewVPCClient(logger logr.Logger, regionID string) (*VPCClient, error) {
 	cli, err := vpc.NewClientWithAccessKey(regionID, AccessKeyId, AccessKeySecret)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create vpc client")
 	}
 	return &VPCClient{
-		Logger: logger.WithValues("client", "vpc"),
-		cli:    cli,
+		Logger:  logger.WithValues("client", "vpc"),
+		cli:     cli,
+		limiter: Limiter(regionID, "vpc"),
 	}, nil
 }
 
 type VPCClient struct {
 	logr.Logger
-	cli *vpc.Client
+	cli     *vpc.Client
+	limiter *rate.Limiter
 }
 
 func (s *VPCClient) Describe(id string) (*infrav1.VPC, error) {
@@ -38,13 +49,19 @@ func (s *VPCClient) Describe(id string) (*infrav1.VPC, error) {
 	var resp *vpc.DescribeVpcsResponse
 	if err := retry.Try(retry.DefaultBackOf, func() error {
 		logger.Info("requesting")
-		var err error
-		resp, err = s.cli.DescribeVpcs(req)
+		err := Call(s.limiter, "vpc", "DescribeVpcs", func() error {
+			var err error
+			resp, err = s.cli.DescribeVpcs(req)
+			return err
+		})
 		if err != nil {
 			logger.Info("error: " + err.Error())
 		}
 		return errors.Wrap(err, "DescribeVpcs")
 	}); err != nil {
+		if retry.IsNotFound(err) {
+			return nil, nil
+		}
 		return nil, err
 	}
 
@@ -65,8 +82,11 @@ func (s *VPCClient) Create(spec infrav1.VPCSpec) (string, error) {
 	var resp *vpc.CreateVpcResponse
 	if err := retry.Try(retry.DefaultBackOf, func() error {
 		logger.Info("requesting", "request", req)
-		var err error
-		resp, err = s.cli.CreateVpc(req)
+		err := Call(s.limiter, "vpc", "CreateVpc", func() error {
+			var err error
+			resp, err = s.cli.CreateVpc(req)
+			return err
+		})
 		if err != nil {
 			logger.Info("error: " + err.Error())
 		}
@@ -120,11 +140,16 @@ func (s *VPCClient) Delete(id string) error {
 
 	if err := retry.Try(retry.DefaultBackOf, func() error {
 		logger.Info("requesting")
-		var err error
-		_, err = s.cli.DeleteVpc(req)
+		err := Call(s.limiter, "vpc", "DeleteVpc", func() error {
+			_, err := s.cli.DeleteVpc(req)
+			return err
+		})
 		if err != nil {
 			logger.Info("error: " + err.Error())
-			if e, ok := err.(sdkerr.Error); ok && strings.Contains(e.ErrorCode(), "Dependency") {
+			if retry.IsNotFound(err) {
+				return nil
+			}
+			if retry.IsConflict(err) {
 				return retry.ErrRetry
 			}
 		}
@@ -147,13 +172,19 @@ func (s *VPCClient) DescribeNatGateway(id string) (*infrav1.NatGateway, error) {
 	var resp *vpc.DescribeNatGatewaysResponse
 	if err := retry.Try(retry.DefaultBackOf, func() error {
 		logger.Info("requesting")
-		var err error
-		resp, err = s.cli.DescribeNatGateways(req)
+		err := Call(s.limiter, "vpc", "DescribeNatGateways", func() error {
+			var err error
+			resp, err = s.cli.DescribeNatGateways(req)
+			return err
+		})
 		if err != nil {
 			logger.Info("error: " + err.Error())
 		}
 		return errors.Wrap(err, "DescribeNatGateways")
 	}); err != nil {
+		if retry.IsNotFound(err) {
+			return nil, nil
+		}
 		return nil, err
 	}
 
@@ -174,8 +205,11 @@ func (s *VPCClient) CreateNatGateway(spec infrav1.NatGatewaySpec, vpcID string)
 	var resp *vpc.CreateNatGatewayResponse
 	if err := retry.Try(retry.DefaultBackOf, func() error {
 		logger.Info("requesting", "request", req)
-		var err error
-		resp, err = s.cli.CreateNatGateway(req)
+		err := Call(s.limiter, "vpc", "CreateNatGateway", func() error {
+			var err error
+			resp, err = s.cli.CreateNatGateway(req)
+			return err
+		})
 		if err != nil {
 			logger.Info("error: " + err.Error())
 		}
@@ -229,10 +263,15 @@ func (s *VPCClient) DeleteGateway(id string) error {
 
 	if err := retry.Try(retry.DefaultBackOf, func() error {
 		logger.Info("requesting")
-		var err error
-		_, err = s.cli.DeleteNatGateway(req)
+		err := Call(s.limiter, "vpc", "DeleteNatGateway", func() error {
+			_, err := s.cli.DeleteNatGateway(req)
+			return err
+		})
 		if err != nil {
-			if e, ok := err.(sdkerr.Error); ok && strings.Contains(e.ErrorCode(), "Dependency") {
+			if retry.IsNotFound(err) {
+				return nil
+			}
+			if retry.IsConflict(err) {
 				return retry.ErrRetry
 			}
 			logger.Info("error: " + err.Error())
@@ -256,13 +295,19 @@ func (s *VPCClient) DescribeEIP(id string) (*infrav1.EIP, error) {
 	var resp *vpc.DescribeEipAddressesResponse
 	if err := retry.Try(retry.DefaultBackOf, func() error {
 		logger.Info("requesting")
-		var err error
-		resp, err = s.cli.DescribeEipAddresses(req)
+		err := Call(s.limiter, "vpc", "DescribeEipAddresses", func() error {
+			var err error
+			resp, err = s.cli.DescribeEipAddresses(req)
+			return err
+		})
 		if err != nil {
 			logger.Error(err, err.Error())
 		}
 		return errors.Wrap(err, "DescribeEipAddresses")
 	}); err != nil {
+		if retry.IsNotFound(err) {
+			return nil, nil
+		}
 		return nil, err
 	}
 
@@ -283,8 +328,11 @@ func (s *VPCClient) CreateEIP(spec infrav1.EIPSpec, vpcID string) (string, error
 	var resp *vpc.AllocateEipAddressResponse
 	if err := retry.Try(retry.DefaultBackOf, func() error {
 		logger.Info("requesting", "request", req)
-		var err error
-		resp, err = s.cli.AllocateEipAddress(req)
+		err := Call(s.limiter, "vpc", "AllocateEipAddress", func() error {
+			var err error
+			resp, err = s.cli.AllocateEipAddress(req)
+			return err
+		})
 		if err != nil {
 			logger.Info("error: " + err.Error())
 		}
@@ -340,10 +388,15 @@ func (s *VPCClient) DeleteEIP(id string) error {
 
 	if err := retry.Try(retry.DefaultBackOf, func() error {
 		logger.Info("requesting")
-		var err error
-		_, err = s.cli.ReleaseEipAddress(req)
+		err := Call(s.limiter, "vpc", "ReleaseEipAddress", func() error {
+			_, err := s.cli.ReleaseEipAddress(req)
+			return err
+		})
 		if err != nil {
-			if e, ok := err.(sdkerr.Error); ok && strings.Contains(e.ErrorCode(), "Dependency") {
+			if retry.IsNotFound(err) {
+				return nil
+			}
+			if retry.IsConflict(err) {
 				return retry.ErrRetry
 			}
 			logger.Info("error: " + err.Error())
@@ -368,9 +421,13 @@ func (s *VPCClient) UnassociateEipToNatGateway(eip *infrav1.EIP, ngw *infrav1.Na
 
 	return retry.Try(retry.DefaultBackOf, func() error {
 		logger.Info("requesting")
-		_, err := s.cli.UnassociateEipAddress(req)
+		err := Call(s.limiter, "vpc", "UnassociateEipAddress", func() error {
+			_, err := s.cli.UnassociateEipAddress(req)
+			return err
+		})
 		if err != nil {
-			if serr, ok := err.(sdkerr.Error); ok && serr.ErrorCode() == "InvalidIpStatus.HasBeenUsedBySnatTable" {
+			// the EIP stays busy until the SNAT entries using it are gone
+			if retry.IsConflict(err) {
 				return retry.ErrRetry
 			}
 
@@ -395,9 +452,12 @@ func (s *VPCClient) AssociateEipToNatGateway(eip *infrav1.EIP, ngw *infrav1.NatG
 
 	return retry.Try(retry.DefaultBackOf, func() error {
 		logger.Info("requesting")
-		_, err := s.cli.AssociateEipAddress(req)
+		err := Call(s.limiter, "vpc", "AssociateEipAddress", func() error {
+			_, err := s.cli.AssociateEipAddress(req)
+			return err
+		})
 		if err != nil {
-			if serr, ok := err.(sdkerr.Error); ok && serr.ErrorCode() == "BIND_INSTANCE_HAVE_PORTMAP_OR_BIND_EIP" {
+			if retry.Code(err) == "BIND_INSTANCE_HAVE_PORTMAP_OR_BIND_EIP" {
 				return nil
 			}
 
@@ -410,24 +470,38 @@ func (s *VPCClient) AssociateEipToNatGateway(eip *infrav1.EIP, ngw *infrav1.NatG
 	})
 }
 
-func (s *VPCClient) CreateSnatEntry(eip *infrav1.EIP, ngw *infrav1.NatGateway, vswID string) (string, error) {
-	logger := s.WithValues("SDKAction", "CreateSnatEntry", "eip", eip.AllocationId, "ngw", ngw.NatGatewayId, "vsw", vswID)
+// CreateSnatEntry creates entry in the SNAT table and returns its ID. An existing entry for the same
+// source is adopted, so a retried reconcile does not fail on the entry it created last time.
+func (s *VPCClient) CreateSnatEntry(tableID string, entry infrav1.SnatEntry, name string) (string, error) {
+	logger := s.WithValues("SDKAction", "CreateSnatEntry", "table", tableID, "source", entry.Source(), "snatIp", entry.SnatIp)
 
 	req := vpc.CreateCreateSnatEntryRequest()
 	req.Scheme = "https"
-	req.SnatTableId = ngw.SnatTableIds.SnatTableId[0]
-	req.SnatIp = eip.IpAddress
-	req.SourceVSwitchId = vswID
-	req.SnatEntryName = ngw.Name
+	req.SnatTableId = tableID
+	req.SnatIp = entry.SnatIp
+	if len(entry.SourceCIDR) > 0 {
+		req.SourceCIDR = entry.SourceCIDR
+	} else {
+		req.SourceVSwitchId = entry.SourceVSwitchId
+	}
+	req.SnatEntryName = name
 
 	var resp *vpc.CreateSnatEntryResponse
 	err := retry.Try(retry.DefaultBackOf, func() error {
 		logger.Info("requesting")
-		var err error
-		resp, err = s.cli.CreateSnatEntry(req)
+		err := Call(s.limiter, "vpc", "CreateSnatEntry", func() error {
+			var err error
+			resp, err = s.cli.CreateSnatEntry(req)
+			return err
+		})
 		if err != nil {
-			if serr, ok := err.(sdkerr.Error); ok && serr.ErrorCode() == "Forbidden.SourceVSwitchId.Duplicated" {
-				return nil
+			// Forbidden.SourceVSwitchId.Duplicated and its SourceCIDR counterpart
+			if strings.HasPrefix(retry.Code(err), "Forbidden.Source") && strings.HasSuffix(retry.Code(err), ".Duplicated") {
+				return errSnatEntryExists
+			}
+			// the table is busy while an earlier entry is being created or deleted
+			if retry.IsConflict(err) {
+				return retry.ErrRetry
 			}
 
 			logger.Info("error: " + err.Error())
@@ -437,41 +511,437 @@ func (s *VPCClient) CreateSnatEntry(eip *infrav1.EIP, ngw *infrav1.NatGateway, v
 		logger.Info("success")
 		return nil
 	})
+	if errors.Cause(err) == errSnatEntryExists {
+		entries, err := s.DescribeSnatEntries(tableID)
+		if err != nil {
+			return "", errors.Wrap(err, "DescribeSnatEntries")
+		}
+		for i := range entries {
+			existing := infrav1.SnatEntryFromTable(&entries[i])
+			if existing.Source() == entry.Source() {
+				return existing.SnatEntryId, nil
+			}
+		}
+		return "", errors.Errorf("snat entry for %v not found in %v", entry.Source(), tableID)
+	}
 	if err != nil {
 		return "", err
 	}
 	return resp.SnatEntryId, nil
 }
 
-func (s *VPCClient) DeleteSnatEntry(nat *infrav1.Nat) error {
-	logger := s.WithValues("SDKAction", "DeleteSnatEntry", "SnatEntryId", nat.SnatEntryId)
+func (s *VPCClient) DescribeSnatEntries(snatTableID string) ([]vpc.SnatTableEntry, error) {
+	logger := s.WithValues("SDKAction", "DescribeSnatEntries", "SnatTableId", snatTableID)
+
+	req := vpc.CreateDescribeSnatTableEntriesRequest()
+	req.Scheme = "https"
+	req.SnatTableId = snatTableID
+	req.PageSize = requests.NewInteger(50)
+
+	var list []vpc.SnatTableEntry
+	for page := 1; ; page++ {
+		req.PageNumber = requests.NewInteger(page)
+
+		var resp *vpc.DescribeSnatTableEntriesResponse
+		if err := retry.Try(retry.DefaultBackOf, func() error {
+			logger.Info("requesting", "page", page)
+			err := Call(s.limiter, "vpc", "DescribeSnatTableEntries", func() error {
+				var err error
+				resp, err = s.cli.DescribeSnatTableEntries(req)
+				return err
+			})
+			if err != nil {
+				logger.Info("error: " + err.Error())
+			}
+			return errors.Wrap(err, "DescribeSnatTableEntries")
+		}); err != nil {
+			return nil, err
+		}
+
+		list = append(list, resp.SnatTableEntries.SnatTableEntry...)
+		if len(list) >= resp.TotalCount || len(resp.SnatTableEntries.SnatTableEntry) == 0 {
+			break
+		}
+	}
+
+	logger.Info("success", "TotalCount", len(list))
+	return list, nil
+}
+
+func (s *VPCClient) DeleteSnatEntry(tableID, entryID string) error {
+	logger := s.WithValues("SDKAction", "DeleteSnatEntry", "SnatTableId", tableID, "SnatEntryId", entryID)
 
 	req := vpc.CreateDeleteSnatEntryRequest()
 	req.Scheme = "https"
-	req.SnatTableId = nat.NatGateway.SnatTableIds.SnatTableId[0]
-	req.SnatEntryId = nat.SnatEntryId
+	req.SnatTableId = tableID
+	req.SnatEntryId = entryID
 
-	err := retry.Try(retry.DefaultBackOf, func() error {
+	return retry.Try(retry.DefaultBackOf, func() error {
 		logger.Info("requesting")
-		_, err := s.cli.DeleteSnatEntry(req)
+		err := Call(s.limiter, "vpc", "DeleteSnatEntry", func() error {
+			_, err := s.cli.DeleteSnatEntry(req)
+			return err
+		})
 		if err != nil {
-			if serr, ok := err.(sdkerr.Error); ok && serr.ErrorCode() == "Forbidden.SourceVSwitchId.Duplicated" {
+			if retry.IsNotFound(err) {
 				return nil
 			}
+			if retry.IsConflict(err) {
+				return retry.ErrRetry
+			}
 
-			if e, ok := err.(sdkerr.Error); ok && strings.Contains(e.ErrorCode(), "NotFound") {
+			logger.Info("error: " + err.Error())
+			return errors.Wrap(err, "DeleteSnatEntry")
+		}
+
+		logger.Info("success")
+		return nil
+	})
+}
+
+func (s *VPCClient) DescribeForwardEntries(forwardTableID string) ([]vpc.ForwardTableEntry, error) {
+	logger := s.WithValues("SDKAction", "DescribeForwardEntries", "ForwardTableId", forwardTableID)
+
+	req := vpc.CreateDescribeForwardTableEntriesRequest()
+	req.Scheme = "https"
+	req.ForwardTableId = forwardTableID
+	req.PageSize = requests.NewInteger(50)
+
+	var list []vpc.ForwardTableEntry
+	for page := 1; ; page++ {
+		req.PageNumber = requests.NewInteger(page)
+
+		var resp *vpc.DescribeForwardTableEntriesResponse
+		if err := retry.Try(retry.DefaultBackOf, func() error {
+			logger.Info("requesting", "page", page)
+			err := Call(s.limiter, "vpc", "DescribeForwardTableEntries", func() error {
+				var err error
+				resp, err = s.cli.DescribeForwardTableEntries(req)
+				return err
+			})
+			if err != nil {
+				logger.Info("error: " + err.Error())
+			}
+			return errors.Wrap(err, "DescribeForwardTableEntries")
+		}); err != nil {
+			return nil, err
+		}
+
+		list = append(list, resp.ForwardTableEntries.ForwardTableEntry...)
+		if len(list) >= resp.TotalCount || len(resp.ForwardTableEntries.ForwardTableEntry) == 0 {
+			break
+		}
+	}
+
+	logger.Info("success", "TotalCount", len(list))
+	return list, nil
+}
+
+func (s *VPCClient) CreateForwardEntry(forwardTableID string, entry infrav1.ForwardEntry, name string) (string, error) {
+	logger := s.WithValues("SDKAction", "CreateForwardEntry", "table", forwardTableID, "entry", entry.Key())
+
+	req := vpc.CreateCreateForwardEntryRequest()
+	req.Scheme = "https"
+	req.ForwardTableId = forwardTableID
+	req.ExternalIp = entry.ExternalIp
+	req.ExternalPort = entry.ExternalPort
+	req.InternalIp = entry.InternalIp
+	req.InternalPort = entry.InternalPort
+	req.IpProtocol = entry.IpProtocol
+	req.ForwardEntryName = name
+
+	var resp *vpc.CreateForwardEntryResponse
+	if err := retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting")
+		err := Call(s.limiter, "vpc", "CreateForwardEntry", func() error {
+			var err error
+			resp, err = s.cli.CreateForwardEntry(req)
+			return err
+		})
+		if err != nil {
+			if retry.IsConflict(err) {
+				return retry.ErrRetry
+			}
+			logger.Info("error: " + err.Error())
+		}
+		return errors.Wrap(err, "CreateForwardEntry")
+	}); err != nil {
+		return "", err
+	}
+
+	logger.Info("success", "ForwardEntryId", resp.ForwardEntryId)
+	return resp.ForwardEntryId, nil
+}
+
+func (s *VPCClient) DeleteForwardEntry(forwardTableID, entryID string) error {
+	logger := s.WithValues("SDKAction", "DeleteForwardEntry", "ForwardTableId", forwardTableID, "ForwardEntryId", entryID)
+
+	req := vpc.CreateDeleteForwardEntryRequest()
+	req.Scheme = "https"
+	req.ForwardTableId = forwardTableID
+	req.ForwardEntryId = entryID
+
+	return retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting")
+		err := Call(s.limiter, "vpc", "DeleteForwardEntry", func() error {
+			_, err := s.cli.DeleteForwardEntry(req)
+			return err
+		})
+		if err != nil {
+			if retry.IsNotFound(err) {
 				return nil
 			}
+			if retry.IsConflict(err) {
+				return retry.ErrRetry
+			}
 
 			logger.Info("error: " + err.Error())
-			return errors.Wrap(err, "DeleteSnatEntry")
+			return errors.Wrap(err, "DeleteForwardEntry")
 		}
 
 		logger.Info("success")
 		return nil
 	})
-	if err != nil {
+}
+
+// AssociateEipToInstance associates an EIP with an ECS or SLB instance, see EipInstanceTypeEcs and EipInstanceTypeSlb.
+func (s *VPCClient) AssociateEipToInstance(eipID, instanceID, instanceType string) error {
+	logger := s.WithValues("SDKAction", "AssociateEipToInstance", "eip", eipID, "instance", instanceID, "type", instanceType)
+
+	req := vpc.CreateAssociateEipAddressRequest()
+	req.Scheme = "https"
+	req.AllocationId = eipID
+	req.InstanceId = instanceID
+	req.InstanceType = instanceType
+
+	return retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting")
+		err := Call(s.limiter, "vpc", "AssociateEipAddress", func() error {
+			_, err := s.cli.AssociateEipAddress(req)
+			return err
+		})
+		if err != nil {
+			// a freshly started instance rejects the association for a short while
+			if retry.IsConflict(err) {
+				return retry.ErrRetry
+			}
+
+			logger.Info("error: " + err.Error())
+			return errors.Wrap(err, "AssociateEipAddress")
+		}
+
+		logger.Info("success")
+		return nil
+	})
+}
+
+func (s *VPCClient) UnassociateEipToInstance(eipID, instanceID, instanceType string) error {
+	logger := s.WithValues("SDKAction", "UnassociateEipToInstance", "eip", eipID, "instance", instanceID, "type", instanceType)
+
+	req := vpc.CreateUnassociateEipAddressRequest()
+	req.Scheme = "https"
+	req.AllocationId = eipID
+	req.InstanceId = instanceID
+	req.InstanceType = instanceType
+
+	return retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting")
+		err := Call(s.limiter, "vpc", "UnassociateEipAddress", func() error {
+			_, err := s.cli.UnassociateEipAddress(req)
+			return err
+		})
+		if err != nil {
+			if retry.IsNotFound(err) {
+				return nil
+			}
+			if retry.IsConflict(err) {
+				return retry.ErrRetry
+			}
+
+			logger.Info("error: " + err.Error())
+			return errors.Wrap(err, "UnassociateEipAddress")
+		}
+
+		logger.Info("success")
+		return nil
+	})
+}
+
+func (s *VPCClient) DescribeCommonBandwidthPackage(id string) (*infrav1.CommonBandwidthPackage, error) {
+	logger := s.WithValues("SDKAction", "DescribeCommonBandwidthPackage", "id", id)
+
+	req := vpc.CreateDescribeCommonBandwidthPackagesRequest()
+	req.Scheme = "https"
+	req.BandwidthPackageId = id
+
+	var resp *vpc.DescribeCommonBandwidthPackagesResponse
+	if err := retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting")
+		err := Call(s.limiter, "vpc", "DescribeCommonBandwidthPackages", func() error {
+			var err error
+			resp, err = s.cli.DescribeCommonBandwidthPackages(req)
+			return err
+		})
+		if err != nil {
+			logger.Error(err, err.Error())
+		}
+		return errors.Wrap(err, "DescribeCommonBandwidthPackages")
+	}); err != nil {
+		if retry.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	logger.Info("success", "TotalCount", resp.TotalCount)
+	if len(resp.CommonBandwidthPackages.CommonBandwidthPackage) == 0 {
+		return nil, nil
+	}
+
+	ret := &infrav1.CommonBandwidthPackage{}
+	ret.FillFrom(&resp.CommonBandwidthPackages.CommonBandwidthPackage[0])
+	return ret, nil
+}
+
+func (s *VPCClient) CreateCommonBandwidthPackage(spec infrav1.CommonBandwidthPackageSpec) (string, error) {
+	logger := s.WithValues("SDKAction", "CreateCommonBandwidthPackage")
+
+	req := spec.ConvertToCreateReq()
+	var resp *vpc.CreateCommonBandwidthPackageResponse
+	if err := retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting", "request", req)
+		err := Call(s.limiter, "vpc", "CreateCommonBandwidthPackage", func() error {
+			var err error
+			resp, err = s.cli.CreateCommonBandwidthPackage(req)
+			return err
+		})
+		if err != nil {
+			logger.Info("error: " + err.Error())
+		}
+		return errors.Wrap(err, "CreateCommonBandwidthPackage")
+	}); err != nil {
+		return "", err
+	}
+
+	logger.Info("success", "BandwidthPackageId", resp.BandwidthPackageId)
+	return resp.BandwidthPackageId, nil
+}
+
+func (s *VPCClient) WaitCommonBandwidthPackageReady(id string) (*infrav1.CommonBandwidthPackage, error) {
+	logger := s.WithValues("SDKAction", "WaitCommonBandwidthPackageReady", "id", id)
+
+	var ret *infrav1.CommonBandwidthPackage
+	if err := retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("describing")
+		var err error
+		ret, err = s.DescribeCommonBandwidthPackage(id)
+		if err != nil {
+			logger.Info("error: " + err.Error())
+			return errors.Wrap(err, "DescribeCommonBandwidthPackage")
+		}
+		if ret == nil {
+			logger.Info("nil result")
+			return retry.ErrRetry
+		}
+		if ret.Status != infrav1.Available {
+			logger.Info("waiting for status: Available, now status: " + ret.Status)
+			return retry.ErrRetry
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	logger.Info("ready")
+	return ret, nil
+}
+
+func (s *VPCClient) DeleteCommonBandwidthPackage(id string) error {
+	logger := s.WithValues("SDKAction", "DeleteCommonBandwidthPackage", "id", id)
+
+	req := vpc.CreateDeleteCommonBandwidthPackageRequest()
+	req.Scheme = "https"
+	req.BandwidthPackageId = id
+
+	if err := retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting")
+		err := Call(s.limiter, "vpc", "DeleteCommonBandwidthPackage", func() error {
+			_, err := s.cli.DeleteCommonBandwidthPackage(req)
+			return err
+		})
+		if err != nil {
+			if retry.IsNotFound(err) {
+				return nil
+			}
+			if retry.IsConflict(err) {
+				return retry.ErrRetry
+			}
+			logger.Info("error: " + err.Error())
+		}
+		return errors.Wrap(err, "DeleteCommonBandwidthPackage")
+	}); err != nil {
 		return err
 	}
+
+	logger.Info("success")
 	return nil
 }
+
+func (s *VPCClient) AddCommonBandwidthPackageIp(packageID, eipID string) error {
+	logger := s.WithValues("SDKAction", "AddCommonBandwidthPackageIp", "id", packageID, "eip", eipID)
+
+	req := vpc.CreateAddCommonBandwidthPackageIpRequest()
+	req.Scheme = "https"
+	req.BandwidthPackageId = packageID
+	req.IpInstanceId = eipID
+
+	return retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting")
+		err := Call(s.limiter, "vpc", "AddCommonBandwidthPackageIp", func() error {
+			_, err := s.cli.AddCommonBandwidthPackageIp(req)
+			return err
+		})
+		if err != nil {
+			if retry.IsConflict(err) {
+				return retry.ErrRetry
+			}
+
+			logger.Info("error: " + err.Error())
+			return errors.Wrap(err, "AddCommonBandwidthPackageIp")
+		}
+
+		logger.Info("success")
+		return nil
+	})
+}
+
+func (s *VPCClient) RemoveCommonBandwidthPackageIp(packageID, eipID string) error {
+	logger := s.WithValues("SDKAction", "RemoveCommonBandwidthPackageIp", "id", packageID, "eip", eipID)
+
+	req := vpc.CreateRemoveCommonBandwidthPackageIpRequest()
+	req.Scheme = "https"
+	req.BandwidthPackageId = packageID
+	req.IpInstanceId = eipID
+
+	return retry.Try(retry.DefaultBackOf, func() error {
+		logger.Info("requesting")
+		err := Call(s.limiter, "vpc", "RemoveCommonBandwidthPackageIp", func() error {
+			_, err := s.cli.RemoveCommonBandwidthPackageIp(req)
+			return err
+		})
+		if err != nil {
+			if retry.IsNotFound(err) {
+				return nil
+			}
+			if retry.IsConflict(err) {
+				return retry.ErrRetry
+			}
+
+			logger.Info("error: " + err.Error())
+			return errors.Wrap(err, "RemoveCommonBandwidthPackageIp")
+		}
+
+		logger.Info("success")
+		return nil
+	})
+}
@@ -1,12 +1,11 @@
 package aliyun
 
 import (
-	"strings"
-
-	sdkerr "github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
 	infrav1 "sigs.k8s.io/cluster-api-provider-alicloud/api/v1alpha2"
 	"sigs.k8s.io/cluster-api-provider-alicloud/pkg/aliyun/retry"
 )
@@ -17,14 +16,16 @@ func NewVSwitchClient(logger logr.Logger, regionID string) (*VSwitchClient, erro
 		return nil, errors.Wrap(err, "failed to create vswitch client")
 	}
 	return &VSwitchClient{
-		Logger: logger.WithValues("client", "vswitch"),
-		cli:    cli,
+		Logger:  logger.WithValues("client", "vswitch"),
+		cli:     cli,
+		limiter: Limiter(regionID, "vpc"),
 	}, nil
 }
 
 type VSwitchClient struct {
 	logr.Logger
-	cli *vpc.Client
+	cli     *vpc.Client
+	limiter *rate.Limiter
 }
 
 func (s *VSwitchClient) Describe(id string) (*infrav1.VSwitch, error) {
@@ -36,13 +37,19 @@ func (s *VSwitchClient) Describe(id string) (*infrav1.VSwitch, error) {
 	var resp *vpc.DescribeVSwitchesResponse
 	if err := retry.Try(retry.DefaultBackOf, func() error {
 		logger.Info("requesting")
-		var err error
-		resp, err = s.cli.DescribeVSwitches(req)
+		err := Call(s.limiter, "vpc", "DescribeVSwitches", func() error {
+			var err error
+			resp, err = s.cli.DescribeVSwitches(req)
+			return err
+		})
 		if err != nil {
 			logger.Info("error: " + err.Error())
 		}
 		return errors.Wrap(err, "DescribeVSwitches")
 	}); err != nil {
+		if retry.IsNotFound(err) {
+			return nil, nil
+		}
 		return nil, err
 	}
 
@@ -56,6 +63,49 @@ func (s *VSwitchClient) Describe(id string) (*infrav1.VSwitch, error) {
 	return ret, nil
 }
 
+// DescribeByVpc lists all VSwitches of a VPC.
+func (s *VSwitchClient) DescribeByVpc(vpcID string) ([]infrav1.VSwitch, error) {
+	logger := s.WithValues("SDKAction", "DescribeByVpc", "VpcId", vpcID)
+
+	req := vpc.CreateDescribeVSwitchesRequest()
+	req.Scheme = "https"
+	req.VpcId = vpcID
+	req.PageSize = requests.NewInteger(50)
+
+	var list []infrav1.VSwitch
+	for page := 1; ; page++ {
+		req.PageNumber = requests.NewInteger(page)
+
+		var resp *vpc.DescribeVSwitchesResponse
+		if err := retry.Try(retry.DefaultBackOf, func() error {
+			logger.Info("requesting", "page", page)
+			err := Call(s.limiter, "vpc", "DescribeVSwitches", func() error {
+				var err error
+				resp, err = s.cli.DescribeVSwitches(req)
+				return err
+			})
+			if err != nil {
+				logger.Info("error: " + err.Error())
+			}
+			return errors.Wrap(err, "DescribeVSwitches")
+		}); err != nil {
+			return nil, err
+		}
+
+		for i := range resp.VSwitches.VSwitch {
+			item := infrav1.VSwitch{}
+			item.FillFrom(&resp.VSwitches.VSwitch[i])
+			list = append(list, item)
+		}
+		if len(list) >= resp.TotalCount || len(resp.VSwitches.VSwitch) == 0 {
+			break
+		}
+	}
+
+	logger.Info("success", "TotalCount", len(list))
+	return list, nil
+}
+
 func (s *VSwitchClient) Create(spec infrav1.VSwitchSpec, zoneID string, vpcId string) (string, error) {
 	logger := s.WithValues("SDKAction", "Create")
 
@@ -63,8 +113,11 @@ func (s *VSwitchClient) Create(spec infrav1.VSwitchSpec, zoneID string, vpcId st
 	var resp *vpc.CreateVSwitchResponse
 	if err := retry.Try(retry.DefaultBackOf, func() error {
 		logger.Info("requesting", "request", req)
-		var err error
-		resp, err = s.cli.CreateVSwitch(req)
+		err := Call(s.limiter, "vpc", "CreateVSwitch", func() error {
+			var err error
+			resp, err = s.cli.CreateVSwitch(req)
+			return err
+		})
 		if err != nil {
 			logger.Info("error: " + err.Error())
 		}
@@ -118,16 +171,16 @@ func (s *VSwitchClient) Delete(id string) error {
 
 	if err := retry.Try(retry.DefaultBackOf, func() error {
 		logger.Info("requesting")
-		var err error
-		_, err = s.cli.DeleteVSwitch(req)
+		err := Call(s.limiter, "vpc", "DeleteVSwitch", func() error {
+			_, err := s.cli.DeleteVSwitch(req)
+			return err
+		})
 		if err != nil {
-			if e, ok := err.(sdkerr.Error); ok {
-				if strings.Contains(e.Message(), "not found") {
-					return nil
-				}
-				if strings.Contains(e.ErrorCode(), "Dependency") {
-					return retry.ErrRetry
-				}
+			if retry.IsNotFound(err) {
+				return nil
+			}
+			if retry.IsConflict(err) {
+				return retry.ErrRetry
 			}
 			logger.Info("error: " + err.Error())
 		}
@@ -0,0 +1,101 @@
+// Package healthz serves the health probe endpoints of the manager.
+package healthz
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// Check reports a failed dependency of the manager with a non-nil error.
+type Check func() error
+
+// Server serves /healthz, which only tells the process is alive, and /readyz, which runs ReadyChecks.
+// It runs on every replica, leader or not.
+type Server struct {
+	Addr        string
+	ReadyChecks map[string]Check
+	Log         logr.Logger
+}
+
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+func (s *Server) Start(stop <-chan struct{}) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+	mux.HandleFunc("/readyz", s.serveReady)
+
+	listener, err := net.Listen("tcp", s.Addr)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{Handler: mux}
+	go func() {
+		<-stop
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(ctx)
+	}()
+
+	if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// serveReady runs every ready check and answers 503 listing the failed ones, so that the kubelet
+// probe event names the cause.
+func (s *Server) serveReady(w http.ResponseWriter, _ *http.Request) {
+	names := make([]string, 0, len(s.ReadyChecks))
+	for name := range s.ReadyChecks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var failed []string
+	for _, name := range names {
+		if err := s.ReadyChecks[name](); err != nil {
+			if s.Log != nil {
+				s.Log.Error(err, "readiness check failed", "check", name)
+			}
+			failed = append(failed, fmt.Sprintf("%s: %v", name, err))
+		}
+	}
+
+	if len(failed) > 0 {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		for _, line := range failed {
+			_, _ = fmt.Fprintln(w, line)
+		}
+		return
+	}
+	_, _ = w.Write([]byte("ok"))
+}
+
+// Cached wraps check so that its result is reused for ttl, keeping frequent probes from spending
+// the cloud API quota.
+func Cached(ttl time.Duration, check Check) Check {
+	var mu sync.Mutex
+	var last time.Time
+	var lastErr error
+	return func() error {
+		mu.Lock()
+		defer mu.Unlock()
+		if !last.IsZero() && time.Since(last) < ttl {
+			return lastErr
+		}
+		lastErr = check()
+		last = time.Now()
+		return lastErr
+	}
+}
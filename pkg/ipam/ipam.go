@@ -0,0 +1,97 @@
+// Package ipam allocates IPv4 subnets out of a VPC CIDR block.
+package ipam
+
+import (
+	"encoding/binary"
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+// Allocate returns the first subnet of the given prefix length inside pool that overlaps none of used.
+// Entries of used that are not IPv4 CIDR blocks are ignored, so pod and service ranges of a
+// dual-stack cluster can be passed as they are.
+func Allocate(pool string, prefix int, used []string) (string, error) {
+	_, poolNet, err := net.ParseCIDR(pool)
+	if err != nil || poolNet.IP.To4() == nil {
+		return "", errors.Errorf("invalid IPv4 CIDR block %q", pool)
+	}
+	poolOnes, _ := poolNet.Mask.Size()
+	if prefix < poolOnes || prefix > 32 {
+		return "", errors.Errorf("cannot allocate a /%d subnet from %s", prefix, pool)
+	}
+
+	var taken []*net.IPNet
+	for _, cidr := range used {
+		if _, n, err := net.ParseCIDR(cidr); err == nil && n.IP.To4() != nil {
+			taken = append(taken, n)
+		}
+	}
+
+	size := uint64(1) << uint(32-prefix)
+	start := uint64(toUint32(poolNet.IP))
+	end := start + (uint64(1) << uint(32-poolOnes))
+	for next := start; next+size <= end; {
+		candidate := &net.IPNet{IP: fromUint32(uint32(next)), Mask: net.CIDRMask(prefix, 32)}
+		skip := next + size
+		free := true
+		for _, n := range taken {
+			if !overlaps(candidate, n) {
+				continue
+			}
+			free = false
+			// jump past the taken block, keeping the candidate aligned to its size
+			ones, _ := n.Mask.Size()
+			if blockEnd := uint64(toUint32(n.IP)) + (uint64(1) << uint(32-ones)); blockEnd > skip {
+				skip = (blockEnd + size - 1) / size * size
+			}
+		}
+		if free {
+			return candidate.String(), nil
+		}
+		next = skip
+	}
+	return "", errors.Errorf("no free /%d subnet left in %s", prefix, pool)
+}
+
+// Overlaps reports whether two CIDR blocks share any address. Blocks of different IP versions never overlap.
+func Overlaps(a, b string) (bool, error) {
+	_, na, err := net.ParseCIDR(a)
+	if err != nil {
+		return false, errors.Wrapf(err, "parse %q", a)
+	}
+	_, nb, err := net.ParseCIDR(b)
+	if err != nil {
+		return false, errors.Wrapf(err, "parse %q", b)
+	}
+	return overlaps(na, nb), nil
+}
+
+// Contains reports whether the CIDR block inner lies entirely inside outer.
+func Contains(outer, inner string) (bool, error) {
+	_, no, err := net.ParseCIDR(outer)
+	if err != nil {
+		return false, errors.Wrapf(err, "parse %q", outer)
+	}
+	_, ni, err := net.ParseCIDR(inner)
+	if err != nil {
+		return false, errors.Wrapf(err, "parse %q", inner)
+	}
+	outerOnes, outerBits := no.Mask.Size()
+	innerOnes, innerBits := ni.Mask.Size()
+	return outerBits == innerBits && innerOnes >= outerOnes && no.Contains(ni.IP), nil
+}
+
+func overlaps(a, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
+
+func toUint32(ip net.IP) uint32 {
+	return binary.BigEndian.Uint32(ip.To4())
+}
+
+func fromUint32(v uint32) net.IP {
+	ip := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(ip, v)
+	return ip
+}